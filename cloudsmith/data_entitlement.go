@@ -126,6 +126,11 @@ func dataSourceEntitlementRead(d *schema.ResourceData, m interface{}) error {
 	}
 
 	tokens := flattenEntitlementToken(entitlementList)
+	for _, token := range tokens {
+		token := token.(map[string]interface{})
+		token["token"] = pc.sensitiveOutput(token["token"].(string), *showTokenVal)
+	}
+
 	if err := d.Set("entitlement_tokens", tokens); err != nil {
 		return err
 	}
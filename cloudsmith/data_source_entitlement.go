@@ -0,0 +1,208 @@
+package cloudsmith
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// findEntitlementByName returns the single entitlement token in a repository
+// with exactly the given name.
+func findEntitlementByName(pc *providerConfig, namespace, repository, name string) (*cloudsmith.RepositoryToken, error) {
+	tokens, err := retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.RepositoryToken, *http.Response, error) {
+		req := pc.APIClient.EntitlementsApi.EntitlementsList(pc.Auth, namespace, repository)
+		req = req.Page(page)
+		req = req.PageSize(pageSize)
+		req = req.Query(fmt.Sprintf("name:%q", name))
+		req = req.ShowTokens(true)
+		return pc.APIClient.EntitlementsApi.EntitlementsListExecute(req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the search is a fuzzy match, so filter down to exact matches
+	matches := []cloudsmith.RepositoryToken{}
+	for _, token := range tokens {
+		if token.GetName() == name {
+			matches = append(matches, token)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no entitlement named '%s' found in repository %s.%s", name, namespace, repository)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf(
+			"%d entitlements named '%s' found in repository %s.%s, use slug_perm to select one",
+			len(matches), name, namespace, repository,
+		)
+	}
+}
+
+func dataSourceEntitlementSingleRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+	repository := requiredString(d, "repository")
+
+	var entitlement *cloudsmith.RepositoryToken
+	var err error
+
+	if slugPerm := optionalString(d, "slug_perm"); slugPerm != nil {
+		req := pc.APIClient.EntitlementsApi.EntitlementsRead(pc.Auth, namespace, repository, *slugPerm)
+		req = req.ShowTokens(true)
+		entitlement, _, err = pc.APIClient.EntitlementsApi.EntitlementsReadExecute(req)
+	} else {
+		entitlement, err = findEntitlementByName(pc, namespace, repository, requiredString(d, "name"))
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("created_at", timeToString(entitlement.GetCreatedAt()))
+	d.Set("is_active", entitlement.GetIsActive())
+	d.Set("limit_bandwidth", entitlement.GetLimitBandwidth())
+	d.Set("limit_bandwidth_unit", entitlement.GetLimitBandwidthUnit())
+	d.Set("limit_date_range_from", timeToString(entitlement.GetLimitDateRangeFrom()))
+	d.Set("limit_date_range_to", timeToString(entitlement.GetLimitDateRangeTo()))
+	d.Set("limit_num_clients", entitlement.GetLimitNumClients())
+	d.Set("limit_num_downloads", entitlement.GetLimitNumDownloads())
+	d.Set("limit_package_query", entitlement.GetLimitPackageQuery())
+	d.Set("limit_path_query", entitlement.GetLimitPathQuery())
+	d.Set("name", entitlement.GetName())
+	d.Set("scheduled_reset_at", timeToString(entitlement.GetScheduledResetAt()))
+	d.Set("scheduled_reset_period", entitlement.GetScheduledResetPeriod())
+	d.Set("slug_perm", entitlement.GetSlugPerm())
+	d.Set("token", pc.sensitiveOutput(entitlement.GetToken(), requiredBool(d, "reveal_token")))
+	d.Set("updated_at", timeToString(entitlement.GetUpdatedAt()))
+
+	d.SetId(entitlement.GetSlugPerm())
+
+	return nil
+}
+
+// dataSourceEntitlement returns the schema and implementation for the data
+// source that looks up a single entitlement token, e.g. one provisioned in
+// another Terraform state.
+//
+//nolint:funlen
+func dataSourceEntitlement() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceEntitlementSingleRead,
+
+		Schema: map[string]*schema.Schema{
+			"created_at": {
+				Type:        schema.TypeString,
+				Description: "ISO 8601 timestamp at which the entitlement was created.",
+				Computed:    true,
+			},
+			"is_active": {
+				Type:        schema.TypeBool,
+				Description: "If enabled, the token will allow downloads based on configured restrictions (if any).",
+				Computed:    true,
+			},
+			"limit_bandwidth": {
+				Type:        schema.TypeInt,
+				Description: "The maximum download bandwidth allowed for the token.",
+				Computed:    true,
+			},
+			"limit_bandwidth_unit": {
+				Type:        schema.TypeString,
+				Description: "Unit of bandwidth for the maximum download bandwidth.",
+				Computed:    true,
+			},
+			"limit_date_range_from": {
+				Type:        schema.TypeString,
+				Description: "The starting date/time the token is allowed to be used from.",
+				Computed:    true,
+			},
+			"limit_date_range_to": {
+				Type:        schema.TypeString,
+				Description: "The ending date/time the token is allowed to be used until.",
+				Computed:    true,
+			},
+			"limit_num_clients": {
+				Type:        schema.TypeInt,
+				Description: "The maximum number of unique clients allowed for the token.",
+				Computed:    true,
+			},
+			"limit_num_downloads": {
+				Type:        schema.TypeInt,
+				Description: "The maximum number of downloads allowed for the token.",
+				Computed:    true,
+			},
+			"limit_package_query": {
+				Type:        schema.TypeString,
+				Description: "The package-based search query to apply to restrict downloads to.",
+				Computed:    true,
+			},
+			"limit_path_query": {
+				Type:        schema.TypeString,
+				Description: "The path-based search query to apply to restrict downloads to.",
+				Computed:    true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Description:  "The name of the entitlement to look up.",
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"name", "slug_perm"},
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace to which the entitlement belongs.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repository": {
+				Type:         schema.TypeString,
+				Description:  "Repository to which the entitlement belongs.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"reveal_token": {
+				Type: schema.TypeBool,
+				Description: "If enabled, the token is stored in state even when the provider is configured " +
+					"with redact_sensitive_outputs.",
+				Optional: true,
+				Default:  false,
+			},
+			"scheduled_reset_at": {
+				Type:        schema.TypeString,
+				Description: "The time at which the scheduled reset period has elapsed and the token limits will be automatically reset.",
+				Computed:    true,
+			},
+			"scheduled_reset_period": {
+				Type:        schema.TypeString,
+				Description: "The period after which the token limits are automatically reset.",
+				Computed:    true,
+			},
+			"slug_perm": {
+				Type:         schema.TypeString,
+				Description:  "The slug_perm of the entitlement to look up.",
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"name", "slug_perm"},
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Description: "The literal value of the token.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"updated_at": {
+				Type:        schema.TypeString,
+				Description: "ISO 8601 timestamp at which the entitlement was last updated.",
+				Computed:    true,
+			},
+		},
+	}
+}
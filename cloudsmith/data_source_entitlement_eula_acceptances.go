@@ -0,0 +1,168 @@
+package cloudsmith
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
+)
+
+// isEulaAccepted reports whether a EULA has been accepted for an entitlement.
+func isEulaAccepted(t cloudsmith.RepositoryToken) bool {
+	return t.EulaAcceptedAt.IsSet() && t.EulaAcceptedAt.Get() != nil
+}
+
+// flattenEulaAcceptance converts the EULA acceptance record of an entitlement
+// to a map that can be stored in TF state.
+func flattenEulaAcceptance(t cloudsmith.RepositoryToken) map[string]interface{} {
+	acceptance := map[string]interface{}{
+		"accepted":        isEulaAccepted(t),
+		"accepted_at":     "",
+		"accepted_from":   t.GetEulaAcceptedFrom(),
+		"entitlement":     t.GetSlugPerm(),
+		"eula_identifier": "",
+		"eula_number":     0,
+		"eula_required":   t.GetEulaRequired(),
+		"name":            t.GetName(),
+		"user":            t.GetUser(),
+	}
+
+	if isEulaAccepted(t) {
+		acceptance["accepted_at"] = t.GetEulaAcceptedAt().Format(time.RFC3339)
+	}
+	if eula := t.GetEulaAccepted(); t.EulaAccepted.Get() != nil {
+		acceptance["eula_identifier"] = eula.GetIdentifier()
+		acceptance["eula_number"] = int(eula.GetNumber())
+	}
+
+	return acceptance
+}
+
+func dataSourceEntitlementEulaAcceptancesRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+	repository := requiredString(d, "repository")
+
+	entitlements, err := retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.RepositoryToken, *http.Response, error) {
+		req := pc.APIClient.EntitlementsApi.EntitlementsList(pc.Auth, namespace, repository)
+		req = req.Page(page)
+		req = req.PageSize(pageSize)
+		return pc.APIClient.EntitlementsApi.EntitlementsListExecute(req)
+	})
+	if err != nil {
+		return fmt.Errorf("error retrieving entitlements: %w", err)
+	}
+
+	if requiredBool(d, "accepted_only") {
+		entitlements = lo.Filter(entitlements, func(t cloudsmith.RepositoryToken, _ int) bool {
+			return isEulaAccepted(t)
+		})
+	}
+
+	sort.SliceStable(entitlements, func(i, j int) bool {
+		if entitlements[i].GetName() != entitlements[j].GetName() {
+			return entitlements[i].GetName() < entitlements[j].GetName()
+		}
+		return entitlements[i].GetSlugPerm() < entitlements[j].GetSlugPerm()
+	})
+
+	d.Set("acceptances", lo.Map(entitlements, func(t cloudsmith.RepositoryToken, _ int) interface{} {
+		return flattenEulaAcceptance(t)
+	}))
+
+	d.SetId(fmt.Sprintf("%s.%s", namespace, repository))
+
+	return nil
+}
+
+// dataSourceEntitlementEulaAcceptances returns the schema and implementation
+// for the data source that lists which entitlements of a repository have had
+// a EULA accepted, when, from where and by whom, as evidence for legal teams.
+//
+//nolint:funlen
+func dataSourceEntitlementEulaAcceptances() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceEntitlementEulaAcceptancesRead,
+
+		Schema: map[string]*schema.Schema{
+			"acceptances": {
+				Type:        schema.TypeList,
+				Description: "The EULA acceptance records of the entitlements in the repository.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"accepted": {
+							Type:        schema.TypeBool,
+							Description: "If true, a EULA has been accepted for the entitlement.",
+							Computed:    true,
+						},
+						"accepted_at": {
+							Type:        schema.TypeString,
+							Description: "ISO 8601 timestamp at which the EULA was accepted.",
+							Computed:    true,
+						},
+						"accepted_from": {
+							Type:        schema.TypeString,
+							Description: "The IP address from which the EULA was accepted.",
+							Computed:    true,
+						},
+						"entitlement": {
+							Type:        schema.TypeString,
+							Description: "The slug_perm of the entitlement.",
+							Computed:    true,
+						},
+						"eula_identifier": {
+							Type:        schema.TypeString,
+							Description: "The identifier of the accepted EULA.",
+							Computed:    true,
+						},
+						"eula_number": {
+							Type:        schema.TypeInt,
+							Description: "The sequential number of the accepted EULA.",
+							Computed:    true,
+						},
+						"eula_required": {
+							Type:        schema.TypeBool,
+							Description: "If true, a EULA must be accepted before the entitlement can be used.",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "The name of the entitlement.",
+							Computed:    true,
+						},
+						"user": {
+							Type:        schema.TypeString,
+							Description: "The user the entitlement belongs to, if any.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"accepted_only": {
+				Type:        schema.TypeBool,
+				Description: "If true, only entitlements for which a EULA has been accepted are included.",
+				Optional:    true,
+				Default:     false,
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace to which the repository belongs.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repository": {
+				Type:         schema.TypeString,
+				Description:  "Repository whose entitlements are listed.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+		},
+	}
+}
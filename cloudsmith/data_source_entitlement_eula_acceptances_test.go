@@ -0,0 +1,101 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestFlattenEulaAcceptance(t *testing.T) {
+	t.Parallel()
+
+	acceptedAt := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	accepted := cloudsmith.RepositoryToken{
+		Name:             "customer",
+		SlugPerm:         cloudsmith.PtrString("AbCdEfGhIjKl"),
+		EulaRequired:     cloudsmith.PtrBool(true),
+		EulaAccepted:     *cloudsmith.NewNullableEula(&cloudsmith.Eula{Identifier: *cloudsmith.NewNullableString(cloudsmith.PtrString("v2")), Number: *cloudsmith.NewNullableInt64(cloudsmith.PtrInt64(2))}),
+		EulaAcceptedAt:   *cloudsmith.NewNullableTime(&acceptedAt),
+		EulaAcceptedFrom: *cloudsmith.NewNullableString(cloudsmith.PtrString("192.0.2.1")),
+		User:             *cloudsmith.NewNullableString(cloudsmith.PtrString("jane")),
+	}
+
+	want := map[string]interface{}{
+		"accepted":        true,
+		"accepted_at":     "2023-01-02T15:04:05Z",
+		"accepted_from":   "192.0.2.1",
+		"entitlement":     "AbCdEfGhIjKl",
+		"eula_identifier": "v2",
+		"eula_number":     2,
+		"eula_required":   true,
+		"name":            "customer",
+		"user":            "jane",
+	}
+	if got := flattenEulaAcceptance(accepted); !reflect.DeepEqual(got, want) {
+		t.Errorf("flattenEulaAcceptance() = %v, want %v", got, want)
+	}
+
+	notAccepted := cloudsmith.RepositoryToken{Name: "pending", EulaRequired: cloudsmith.PtrBool(true)}
+	got := flattenEulaAcceptance(notAccepted)
+	if got["accepted"] != false || got["accepted_at"] != "" || got["eula_identifier"] != "" {
+		t.Errorf("expected no acceptance, got %v", got)
+	}
+}
+
+// TestAccEntitlementEulaAcceptances_basic creates an entitlement, whose EULA
+// can't have been accepted, and verifies that it is listed unless only
+// accepted EULAs are requested.
+func TestAccEntitlementEulaAcceptances_basic(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEntitlementEulaAcceptancesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.cloudsmith_entitlement_eula_acceptances.all", "acceptances.*", map[string]string{
+						"name":     "tf-test-eula-acceptances",
+						"accepted": "false",
+					}),
+					resource.TestCheckResourceAttr("data.cloudsmith_entitlement_eula_acceptances.accepted", "acceptances.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+var testAccEntitlementEulaAcceptancesConfig = fmt.Sprintf(`
+resource "cloudsmith_repository" "test" {
+	name      = "terraform-acc-test-eula-acceptances"
+	namespace = "%s"
+}
+
+resource "cloudsmith_entitlement" "test" {
+	name       = "tf-test-eula-acceptances"
+	namespace  = cloudsmith_repository.test.namespace
+	repository = cloudsmith_repository.test.slug
+}
+
+data "cloudsmith_entitlement_eula_acceptances" "all" {
+	namespace  = cloudsmith_repository.test.namespace
+	repository = cloudsmith_repository.test.slug
+
+	depends_on = [cloudsmith_entitlement.test]
+}
+
+data "cloudsmith_entitlement_eula_acceptances" "accepted" {
+	namespace     = cloudsmith_repository.test.namespace
+	repository    = cloudsmith_repository.test.slug
+	accepted_only = true
+
+	depends_on = [cloudsmith_entitlement.test]
+}
+`, os.Getenv("CLOUDSMITH_NAMESPACE"))
@@ -0,0 +1,58 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccDataSourceEntitlement creates an entitlement token, then looks it up
+// both by name and by slug_perm and verifies both lookups return the token.
+func TestAccDataSourceEntitlement(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceEntitlementConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.cloudsmith_entitlement.by_name", "slug_perm", "cloudsmith_entitlement.test", "id"),
+					resource.TestCheckResourceAttrPair("data.cloudsmith_entitlement.by_name", "token", "cloudsmith_entitlement.test", "token"),
+					resource.TestCheckResourceAttrPair("data.cloudsmith_entitlement.by_slug_perm", "name", "cloudsmith_entitlement.test", "name"),
+					resource.TestCheckResourceAttrPair("data.cloudsmith_entitlement.by_slug_perm", "limit_num_downloads", "cloudsmith_entitlement.test", "limit_num_downloads"),
+				),
+			},
+		},
+	})
+}
+
+var testAccDataSourceEntitlementConfig = fmt.Sprintf(`
+resource "cloudsmith_repository" "test" {
+	name      = "terraform-acc-test-ent-data"
+	namespace = "%s"
+}
+
+resource "cloudsmith_entitlement" "test" {
+	name                = "Test Entitlement Lookup"
+	namespace           = cloudsmith_repository.test.namespace
+	repository          = cloudsmith_repository.test.slug_perm
+	limit_num_downloads = 100
+}
+
+data "cloudsmith_entitlement" "by_name" {
+	namespace  = cloudsmith_repository.test.namespace
+	repository = cloudsmith_repository.test.slug_perm
+	name       = cloudsmith_entitlement.test.name
+}
+
+data "cloudsmith_entitlement" "by_slug_perm" {
+	namespace  = cloudsmith_repository.test.namespace
+	repository = cloudsmith_repository.test.slug_perm
+	slug_perm  = cloudsmith_entitlement.test.id
+}
+`, os.Getenv("CLOUDSMITH_NAMESPACE"))
@@ -0,0 +1,211 @@
+package cloudsmith
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// flattenFormats converts the package formats returned by the API to a list
+// which can be stored in TF state, along with the slugs of all formats and of
+// those which support upstreams.
+func flattenFormats(formats []cloudsmith.Format) ([]interface{}, []string, []string) {
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i].GetSlug() < formats[j].GetSlug()
+	})
+
+	out := make([]interface{}, 0, len(formats))
+	slugs := []string{}
+	upstreamSlugs := []string{}
+
+	for _, format := range formats {
+		supports := format.GetSupports()
+		upstreams := supports.GetUpstreams()
+
+		out = append(out, map[string]interface{}{
+			"description":            format.GetDescription(),
+			"extensions":             format.GetExtensions(),
+			"name":                   format.GetName(),
+			"premium":                format.GetPremium(),
+			"premium_plan_name":      format.GetPremiumPlanName(),
+			"slug":                   format.GetSlug(),
+			"supports_dependencies":  supports.GetDependencies(),
+			"supports_distributions": supports.GetDistributions(),
+			"supports_file_lists":    supports.GetFileLists(),
+			"supports_metadata":      supports.GetMetadata(),
+			"supports_versioning":    supports.GetVersioning(),
+			"upstream": []interface{}{
+				map[string]interface{}{
+					"auth_modes":             upstreams.GetAuthModes(),
+					"caching":                upstreams.GetCaching(),
+					"indexing":               upstreams.GetIndexing(),
+					"indexing_behavior":      upstreams.GetIndexingBehavior(),
+					"proxying":               upstreams.GetProxying(),
+					"signature_verification": upstreams.GetSignatureVerification(),
+				},
+			},
+		})
+
+		slugs = append(slugs, format.GetSlug())
+		if upstreams.GetCaching() || upstreams.GetProxying() {
+			upstreamSlugs = append(upstreamSlugs, format.GetSlug())
+		}
+	}
+
+	return out, slugs, upstreamSlugs
+}
+
+func dataSourceFormatsRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	req := pc.APIClient.FormatsApi.FormatsList(pc.Auth)
+	formats, _, err := pc.APIClient.FormatsApi.FormatsListExecute(req)
+	if err != nil {
+		return fmt.Errorf("error retrieving formats: %w", err)
+	}
+
+	out, slugs, upstreamSlugs := flattenFormats(formats)
+
+	if err := d.Set("formats", out); err != nil {
+		return fmt.Errorf("error setting formats: %w", err)
+	}
+	d.Set("slugs", slugs)
+	d.Set("upstream_slugs", upstreamSlugs)
+
+	d.SetId("formats")
+
+	return nil
+}
+
+// dataSourceFormats returns the schema and implementation for the data source
+// that lists the package formats supported by Cloudsmith and their
+// capabilities, e.g. to validate the upstream_type of upstreams in a module.
+//
+//nolint:funlen
+func dataSourceFormats() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceFormatsRead,
+
+		Schema: map[string]*schema.Schema{
+			"formats": {
+				Type:        schema.TypeList,
+				Description: "The package formats supported by Cloudsmith.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"description": {
+							Type:        schema.TypeString,
+							Description: "Description of the package format.",
+							Computed:    true,
+						},
+						"extensions": {
+							Type:        schema.TypeList,
+							Description: "The filename extensions of packages in the format.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "Name of the package format.",
+							Computed:    true,
+						},
+						"premium": {
+							Type:        schema.TypeBool,
+							Description: "If true, the package format is only available on premium plans.",
+							Computed:    true,
+						},
+						"premium_plan_name": {
+							Type:        schema.TypeString,
+							Description: "The name of the minimum plan required for the package format, if it is premium.",
+							Computed:    true,
+						},
+						"slug": {
+							Type:        schema.TypeString,
+							Description: "Slug of the package format, as used for upstream_type.",
+							Computed:    true,
+						},
+						"supports_dependencies": {
+							Type:        schema.TypeBool,
+							Description: "If true, package dependencies are supported.",
+							Computed:    true,
+						},
+						"supports_distributions": {
+							Type:        schema.TypeBool,
+							Description: "If true, packages are uploaded for specific distributions.",
+							Computed:    true,
+						},
+						"supports_file_lists": {
+							Type:        schema.TypeBool,
+							Description: "If true, package file lists are supported.",
+							Computed:    true,
+						},
+						"supports_metadata": {
+							Type:        schema.TypeBool,
+							Description: "If true, package metadata is extracted.",
+							Computed:    true,
+						},
+						"supports_versioning": {
+							Type:        schema.TypeBool,
+							Description: "If true, package versions are supported.",
+							Computed:    true,
+						},
+						"upstream": {
+							Type:        schema.TypeList,
+							Description: "The upstream capabilities of the package format.",
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"auth_modes": {
+										Type:        schema.TypeList,
+										Description: "The authentication modes supported by upstreams.",
+										Computed:    true,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+									},
+									"caching": {
+										Type:        schema.TypeBool,
+										Description: "If true, upstreams can cache packages.",
+										Computed:    true,
+									},
+									"indexing": {
+										Type:        schema.TypeBool,
+										Description: "If true, upstream packages are indexed.",
+										Computed:    true,
+									},
+									"indexing_behavior": {
+										Type:        schema.TypeString,
+										Description: "How upstream packages are indexed.",
+										Computed:    true,
+									},
+									"proxying": {
+										Type:        schema.TypeBool,
+										Description: "If true, upstreams can proxy packages.",
+										Computed:    true,
+									},
+									"signature_verification": {
+										Type:        schema.TypeString,
+										Description: "The level of signature verification supported for upstream packages.",
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"slugs": {
+				Type:        schema.TypeList,
+				Description: "The slugs of all supported package formats.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"upstream_slugs": {
+				Type:        schema.TypeList,
+				Description: "The slugs of the package formats which support upstreams.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
@@ -0,0 +1,37 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccDataSourceFormats lists the supported package formats and verifies
+// well known formats are reported with the expected capabilities.
+func TestAccDataSourceFormats(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceFormatsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("data.cloudsmith_formats.test", "slugs.*", "raw"),
+					resource.TestCheckTypeSetElemAttr("data.cloudsmith_formats.test", "upstream_slugs.*", "python"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.cloudsmith_formats.test", "formats.*", map[string]string{
+						"slug":                  "npm",
+						"supports_dependencies": "true",
+						"upstream.0.proxying":   "true",
+					}),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceFormatsConfig = `
+data "cloudsmith_formats" "test" {}
+`
@@ -0,0 +1,218 @@
+package cloudsmith
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
+)
+
+// denyPolicyPreview is a deny policy, existing or candidate, whose package
+// query has been evaluated against a package.
+type denyPolicyPreview struct {
+	Candidate    bool
+	Name         string
+	PackageQuery string
+	SlugPerm     string
+}
+
+func flattenDenyPolicyPreviews(previews []denyPolicyPreview) []interface{} {
+	return lo.Map(previews, func(p denyPolicyPreview, _ int) interface{} {
+		return map[string]interface{}{
+			"candidate":     p.Candidate,
+			"name":          p.Name,
+			"package_query": p.PackageQuery,
+			"slug_perm":     p.SlugPerm,
+		}
+	})
+}
+
+// previewDenyPolicies evaluates the package queries of the given deny policies
+// and candidate policies (package queries keyed by name) against a package,
+// returning those which match it and those for which it can't be determined,
+// each sorted by name. Disabled policies are skipped unless includeDisabled.
+func previewDenyPolicies(
+	policies []cloudsmith.PackageDenyPolicy,
+	candidates map[string]string,
+	includeDisabled bool,
+	pkg packageQueryPackage,
+) ([]denyPolicyPreview, []denyPolicyPreview, error) {
+	previews := []denyPolicyPreview{}
+	for _, policy := range policies {
+		if policy.GetEnabled() || includeDisabled {
+			previews = append(previews, denyPolicyPreview{
+				Name:         policy.GetName(),
+				PackageQuery: policy.GetPackageQueryString(),
+				SlugPerm:     policy.GetSlugPerm(),
+			})
+		}
+	}
+	for name, query := range candidates {
+		if _, err := parsePackageQuery(query); err != nil {
+			return nil, nil, fmt.Errorf("invalid package query for candidate policy %q: %w", name, err)
+		}
+		previews = append(previews, denyPolicyPreview{Candidate: true, Name: name, PackageQuery: query})
+	}
+
+	sort.SliceStable(previews, func(i, j int) bool {
+		return previews[i].Name < previews[j].Name
+	})
+
+	matches := []denyPolicyPreview{}
+	undetermined := []denyPolicyPreview{}
+	for _, preview := range previews {
+		// the API may accept queries which can't be parsed here, whose
+		// result is then undetermined
+		node, err := parsePackageQuery(preview.PackageQuery)
+		if err != nil {
+			undetermined = append(undetermined, preview)
+			continue
+		}
+
+		matched, known := evaluatePackageQuery(node, pkg)
+		switch {
+		case !known:
+			undetermined = append(undetermined, preview)
+		case matched:
+			matches = append(matches, preview)
+		}
+	}
+
+	return matches, undetermined, nil
+}
+
+func dataSourcePackageDenyPolicyPreviewRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+	pkg := packageQueryPackage{
+		Format:  d.Get("package_format").(string),
+		Name:    requiredString(d, "package_name"),
+		Tags:    expandStrings(d, "package_tags"),
+		Version: d.Get("package_version").(string),
+	}
+
+	policies, err := retrieveDenyPolicies(pc, namespace)
+	if err != nil {
+		return fmt.Errorf("error retrieving package deny policies: %w", err)
+	}
+
+	candidates := map[string]string{}
+	for name, query := range d.Get("candidate_policies").(map[string]interface{}) {
+		candidates[name] = query.(string)
+	}
+
+	matches, undetermined, err := previewDenyPolicies(policies, candidates, d.Get("include_disabled").(bool), pkg)
+	if err != nil {
+		return err
+	}
+
+	d.Set("matches", flattenDenyPolicyPreviews(matches))
+	d.Set("undetermined", flattenDenyPolicyPreviews(undetermined))
+
+	d.SetId(fmt.Sprintf("%s.%s.%s", namespace, pkg.Name, pkg.Version))
+
+	return nil
+}
+
+func denyPolicyPreviewSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: description,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"candidate": {
+					Type:        schema.TypeBool,
+					Description: "If true, the policy is one of the candidate_policies rather than an existing policy.",
+					Computed:    true,
+				},
+				"name": {
+					Type:        schema.TypeString,
+					Description: "The name of the policy.",
+					Computed:    true,
+				},
+				"package_query": {
+					Type:        schema.TypeString,
+					Description: "The query used to match the packages to be blocked.",
+					Computed:    true,
+				},
+				"slug_perm": {
+					Type:        schema.TypeString,
+					Description: "The slug_perm of the policy, or empty for candidate policies.",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+// dataSourcePackageDenyPolicyPreview returns the schema and implementation for
+// the data source that reports which package deny policies would block a given
+// package, so new deny patterns can be checked before they block legitimate
+// releases.
+//
+//nolint:funlen
+func dataSourcePackageDenyPolicyPreview() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePackageDenyPolicyPreviewRead,
+
+		Schema: map[string]*schema.Schema{
+			"candidate_policies": {
+				Type:        schema.TypeMap,
+				Description: "Package queries of policies which don't exist yet, keyed by name, to check alongside the existing policies.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"include_disabled": {
+				Type:        schema.TypeBool,
+				Description: "If true, disabled policies are checked too.",
+				Optional:    true,
+				Default:     false,
+			},
+			"matches": denyPolicyPreviewSchema("The policies which would block the package, sorted by name."),
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace whose package deny policies are checked.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"package_format": {
+				Type:         schema.TypeString,
+				Description:  "The format of the package, e.g. python.",
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"package_name": {
+				Type:         schema.TypeString,
+				Description:  "The name of the package.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"package_tags": {
+				Type:        schema.TypeSet,
+				Description: "The tags of the package.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+			"package_version": {
+				Type:         schema.TypeString,
+				Description:  "The version of the package.",
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"undetermined": denyPolicyPreviewSchema(
+				"The policies for which it can't be determined whether they would block the package, because their " +
+					"query uses fields other than name, version, format and tag, or fields of the package which weren't given.",
+			),
+		},
+	}
+}
@@ -0,0 +1,109 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/samber/lo"
+)
+
+func TestPreviewDenyPolicies(t *testing.T) {
+	t.Parallel()
+
+	policies := []cloudsmith.PackageDenyPolicy{
+		{Name: *cloudsmith.NewNullableString(cloudsmith.PtrString("block-django")), PackageQueryString: "name:^django$", Enabled: cloudsmith.PtrBool(true)},
+		{Name: *cloudsmith.NewNullableString(cloudsmith.PtrString("block-old")), PackageQueryString: "version:<2.0", Enabled: cloudsmith.PtrBool(false)},
+		{Name: *cloudsmith.NewNullableString(cloudsmith.PtrString("block-big")), PackageQueryString: "size:>1000", Enabled: cloudsmith.PtrBool(true)},
+	}
+	candidates := map[string]string{"block-python": "format:python"}
+	pkg := packageQueryPackage{Format: "python", Name: "django", Version: "1.11"}
+
+	names := func(previews []denyPolicyPreview) []string {
+		return lo.Map(previews, func(p denyPolicyPreview, _ int) string { return p.Name })
+	}
+
+	matches, undetermined, err := previewDenyPolicies(policies, candidates, false, pkg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := []string{"block-django", "block-python"}; !reflect.DeepEqual(names(matches), want) {
+		t.Errorf("expected matches %v, got %v", want, names(matches))
+	}
+	if want := []string{"block-big"}; !reflect.DeepEqual(names(undetermined), want) {
+		t.Errorf("expected undetermined %v, got %v", want, names(undetermined))
+	}
+
+	matches, _, err = previewDenyPolicies(policies, candidates, true, pkg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := []string{"block-django", "block-old", "block-python"}; !reflect.DeepEqual(names(matches), want) {
+		t.Errorf("expected disabled policies to be included, got %v", names(matches))
+	}
+
+	if _, _, err := previewDenyPolicies(nil, map[string]string{"invalid": "name:"}, false, pkg); err == nil {
+		t.Error("expected an error for an invalid candidate query")
+	}
+}
+
+// TestAccPackageDenyPolicyPreview_basic creates a deny policy and verifies
+// that the preview data source reports it as matching a package it blocks, and
+// not one it doesn't.
+func TestAccPackageDenyPolicyPreview_basic(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPackageDenyPolicyPreviewConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.cloudsmith_package_deny_policy_preview.blocked", "matches.*", map[string]string{
+						"name":      "tf-test-deny-preview",
+						"candidate": "false",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.cloudsmith_package_deny_policy_preview.blocked", "matches.*", map[string]string{
+						"name":      "tf-test-deny-preview-candidate",
+						"candidate": "true",
+					}),
+					resource.TestCheckResourceAttr("data.cloudsmith_package_deny_policy_preview.allowed", "matches.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPackageDenyPolicyPreviewConfig() string {
+	return fmt.Sprintf(`
+resource "cloudsmith_package_deny_policy" "test" {
+	name          = "tf-test-deny-preview"
+	package_query = "name:^tf-test-deny-preview$"
+	namespace     = "%[1]s"
+}
+
+data "cloudsmith_package_deny_policy_preview" "blocked" {
+	namespace       = "%[1]s"
+	package_name    = "tf-test-deny-preview"
+	package_version = "1.0.0"
+
+	candidate_policies = {
+		"tf-test-deny-preview-candidate" = "version:<2.0"
+	}
+
+	depends_on = [cloudsmith_package_deny_policy.test]
+}
+
+data "cloudsmith_package_deny_policy_preview" "allowed" {
+	namespace    = "%[1]s"
+	package_name = "tf-test-deny-preview-allowed"
+
+	depends_on = [cloudsmith_package_deny_policy.test]
+}
+`, os.Getenv("CLOUDSMITH_NAMESPACE"))
+}
@@ -0,0 +1,149 @@
+package cloudsmith
+
+import (
+	"fmt"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
+)
+
+func flattenPackageFiles(files []cloudsmith.PackageFile) []interface{} {
+	return lo.Map(files, func(f cloudsmith.PackageFile, _ int) interface{} {
+		return map[string]interface{}{
+			"cdn_url":         f.GetCdnUrl(),
+			"checksum_md5":    f.GetChecksumMd5(),
+			"checksum_sha1":   f.GetChecksumSha1(),
+			"checksum_sha256": f.GetChecksumSha256(),
+			"checksum_sha512": f.GetChecksumSha512(),
+			"filename":        f.GetFilename(),
+			"is_downloadable": f.GetIsDownloadable(),
+			"is_primary":      f.GetIsPrimary(),
+			"signature_url":   f.GetSignatureUrl(),
+			"size":            int(f.GetSize()),
+			"slug_perm":       f.GetSlugPerm(),
+			"tag":             f.GetTag(),
+		}
+	})
+}
+
+func dataSourcePackageFilePartsRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+	namespace := requiredString(d, "namespace")
+	repository := requiredString(d, "repository")
+	identifier := requiredString(d, "identifier")
+
+	req := pc.APIClient.PackagesApi.PackagesRead(pc.Auth, namespace, repository, identifier)
+	pkg, _, err := pc.APIClient.PackagesApi.PackagesReadExecute(req)
+	if err != nil {
+		return err
+	}
+
+	d.Set("files", flattenPackageFiles(pkg.GetFiles()))
+
+	d.SetId(fmt.Sprintf("%s_%s_%s", namespace, repository, pkg.GetSlugPerm()))
+
+	return nil
+}
+
+// dataSourcePackageFileParts returns the schema and implementation for the
+// data source that lists the files composing a package, so their checksums can
+// be compared against the manifests of the systems which built them.
+//
+//nolint:funlen
+func dataSourcePackageFileParts() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePackageFilePartsRead,
+
+		Schema: map[string]*schema.Schema{
+			"files": {
+				Type:        schema.TypeList,
+				Description: "The files composing the package.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cdn_url": {
+							Type:        schema.TypeString,
+							Description: "The URL of the file to download.",
+							Computed:    true,
+						},
+						"checksum_md5": {
+							Type:        schema.TypeString,
+							Description: "MD5 hash of the file.",
+							Computed:    true,
+						},
+						"checksum_sha1": {
+							Type:        schema.TypeString,
+							Description: "SHA1 hash of the file.",
+							Computed:    true,
+						},
+						"checksum_sha256": {
+							Type:        schema.TypeString,
+							Description: "SHA256 hash of the file.",
+							Computed:    true,
+						},
+						"checksum_sha512": {
+							Type:        schema.TypeString,
+							Description: "SHA512 hash of the file.",
+							Computed:    true,
+						},
+						"filename": {
+							Type:        schema.TypeString,
+							Description: "The name of the file.",
+							Computed:    true,
+						},
+						"is_downloadable": {
+							Type:        schema.TypeBool,
+							Description: "Whether the file can be downloaded.",
+							Computed:    true,
+						},
+						"is_primary": {
+							Type:        schema.TypeBool,
+							Description: "Whether the file is the primary file of the package.",
+							Computed:    true,
+						},
+						"signature_url": {
+							Type:        schema.TypeString,
+							Description: "The URL of the file's signature, if it is signed.",
+							Computed:    true,
+						},
+						"size": {
+							Type:        schema.TypeInt,
+							Description: "The size of the file in bytes.",
+							Computed:    true,
+						},
+						"slug_perm": {
+							Type:        schema.TypeString,
+							Description: "The slug_perm that immutably identifies the file.",
+							Computed:    true,
+						},
+						"tag": {
+							Type:        schema.TypeString,
+							Description: "The tag of the file, which describes its role in the package, if any.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"identifier": {
+				Type:         schema.TypeString,
+				Description:  "The identifier of the package.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "The namespace of the package.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repository": {
+				Type:         schema.TypeString,
+				Description:  "The repository of the package.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+		},
+	}
+}
@@ -0,0 +1,124 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestFlattenPackageFiles(t *testing.T) {
+	t.Parallel()
+
+	files := []cloudsmith.PackageFile{
+		{
+			CdnUrl:         *cloudsmith.NewNullableString(cloudsmith.PtrString("https://dl.cloudsmith.io/my-org/my-repo/hello.txt")),
+			ChecksumSha256: *cloudsmith.NewNullableString(cloudsmith.PtrString("abc123")),
+			Filename:       cloudsmith.PtrString("hello.txt"),
+			IsPrimary:      cloudsmith.PtrBool(true),
+			Size:           cloudsmith.PtrInt64(11),
+		},
+		{
+			Filename: cloudsmith.PtrString("hello.txt.asc"),
+			Tag:      *cloudsmith.NewNullableString(cloudsmith.PtrString("signature")),
+		},
+	}
+
+	want := []interface{}{
+		map[string]interface{}{
+			"cdn_url":         "https://dl.cloudsmith.io/my-org/my-repo/hello.txt",
+			"checksum_md5":    "",
+			"checksum_sha1":   "",
+			"checksum_sha256": "abc123",
+			"checksum_sha512": "",
+			"filename":        "hello.txt",
+			"is_downloadable": false,
+			"is_primary":      true,
+			"signature_url":   "",
+			"size":            11,
+			"slug_perm":       "",
+			"tag":             "",
+		},
+		map[string]interface{}{
+			"cdn_url":         "",
+			"checksum_md5":    "",
+			"checksum_sha1":   "",
+			"checksum_sha256": "",
+			"checksum_sha512": "",
+			"filename":        "hello.txt.asc",
+			"is_downloadable": false,
+			"is_primary":      false,
+			"signature_url":   "",
+			"size":            0,
+			"slug_perm":       "",
+			"tag":             "signature",
+		},
+	}
+
+	if got := flattenPackageFiles(files); !reflect.DeepEqual(got, want) {
+		t.Errorf("flattenPackageFiles() = %v, want %v", got, want)
+	}
+}
+
+// TestAccPackageFileParts_data uploads a raw package and verifies that the
+// data source lists its single file with the checksum of its content.
+func TestAccPackageFileParts_data(t *testing.T) {
+	t.Parallel()
+
+	namespace := os.Getenv("CLOUDSMITH_NAMESPACE")
+	repository := testAccRandomName("package-file-parts")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPackageDataSetup(namespace, repository),
+				Check: func(s *terraform.State) error {
+					return uploadPackageTo(testAccProviderConfig(t), namespace, repository, false)
+				},
+			},
+			{
+				Config: testAccPackageFilePartsDataRead(namespace, repository),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cloudsmith_package_file_parts.test", "files.#", "1"),
+					resource.TestCheckResourceAttr("data.cloudsmith_package_file_parts.test", "files.0.filename", "hello.txt"),
+					resource.TestCheckResourceAttr("data.cloudsmith_package_file_parts.test", "files.0.is_primary", "true"),
+					resource.TestCheckResourceAttr("data.cloudsmith_package_file_parts.test", "files.0.size", "11"),
+					// sha256 of "Hello world"
+					resource.TestCheckResourceAttr(
+						"data.cloudsmith_package_file_parts.test", "files.0.checksum_sha256",
+						"64ec88ca00b268e5ba1a35678a1b5316d212f4f366b2477232534a8aeca37f3c",
+					),
+					resource.TestCheckResourceAttrSet("data.cloudsmith_package_file_parts.test", "files.0.cdn_url"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPackageFilePartsDataRead(namespace, repository string) string {
+	return fmt.Sprintf(`
+		resource "cloudsmith_repository" "test" {
+			name      = "%s"
+			namespace = "%s"
+			replace_packages_by_default = true
+		}
+
+		data "cloudsmith_package_list" "test" {
+			repository = cloudsmith_repository.test.name
+			namespace  = cloudsmith_repository.test.namespace
+		}
+
+		data "cloudsmith_package_file_parts" "test" {
+			repository = cloudsmith_repository.test.name
+			namespace  = cloudsmith_repository.test.namespace
+			identifier = data.cloudsmith_package_list.test.packages[0].slug_perm
+		}
+		`, repository, namespace)
+}
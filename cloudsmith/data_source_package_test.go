@@ -112,6 +112,12 @@ func checkFileContent(filePath string, expectedContent string) error {
 }
 
 func uploadPackage(pc *providerConfig, republish bool) error {
+	return uploadPackageTo(pc, dsPackageTestNamespace, dsPackageTestRepository, republish)
+}
+
+// uploadPackageTo uploads hello.txt to the given repository and waits for it
+// to sync.
+func uploadPackageTo(pc *providerConfig, namespace, repository string, republish bool) error {
 
 	var (
 		fileContent []byte
@@ -129,7 +135,7 @@ func uploadPackage(pc *providerConfig, republish bool) error {
 		Sha256Checksum: cloudsmith.PtrString(fmt.Sprintf("%x", sha256.Sum256(fileContent))),
 	}
 
-	initRequest := pc.APIClient.FilesApi.FilesCreate(pc.Auth, dsPackageTestNamespace, dsPackageTestRepository)
+	initRequest := pc.APIClient.FilesApi.FilesCreate(pc.Auth, namespace, repository)
 	initRequest = initRequest.Data(initPayload)
 	initResponse, _, err := initRequest.Execute()
 	if err != nil {
@@ -176,7 +182,7 @@ func uploadPackage(pc *providerConfig, republish bool) error {
 		PackageFile: rbodyStruct.Identifier,
 	}
 
-	finalizeRequest := pc.APIClient.PackagesApi.PackagesUploadRaw(pc.Auth, dsPackageTestNamespace, dsPackageTestRepository)
+	finalizeRequest := pc.APIClient.PackagesApi.PackagesUploadRaw(pc.Auth, namespace, repository)
 	finalizeRequest = finalizeRequest.Data(finalizePayload)
 	finalizeResponse, _, err := finalizeRequest.Execute()
 	if err != nil {
@@ -186,7 +192,7 @@ func uploadPackage(pc *providerConfig, republish bool) error {
 	// Step 3: wait for package sync
 	for {
 		statusRequest := pc.APIClient.PackagesApi.PackagesStatus(
-			pc.Auth, dsPackageTestNamespace, dsPackageTestRepository, finalizeResponse.GetSlugPerm(),
+			pc.Auth, namespace, repository, finalizeResponse.GetSlugPerm(),
 		)
 		status, _, err := statusRequest.Execute()
 		if err != nil {
@@ -0,0 +1,318 @@
+package cloudsmith
+
+import (
+	"net/http"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func flattenLicensePolicies(policies []cloudsmith.OrganizationPackageLicensePolicy) []interface{} {
+	policyList := make([]interface{}, len(policies))
+
+	for i, p := range policies {
+		policyList[i] = map[string]interface{}{
+			"allow_unknown_licenses":  p.GetAllowUnknownLicenses(),
+			"created_at":              timeToString(p.GetCreatedAt()),
+			"description":             p.GetDescription(),
+			"name":                    p.GetName(),
+			"on_violation_quarantine": p.GetOnViolationQuarantine(),
+			"package_query_string":    p.GetPackageQueryString(),
+			"slug_perm":               p.GetSlugPerm(),
+			"spdx_identifiers":        p.GetSpdxIdentifiers(),
+			"updated_at":              timeToString(p.GetUpdatedAt()),
+		}
+	}
+
+	return policyList
+}
+
+func flattenVulnerabilityPolicies(policies []cloudsmith.OrganizationPackageVulnerabilityPolicy) []interface{} {
+	policyList := make([]interface{}, len(policies))
+
+	for i, p := range policies {
+		policyList[i] = map[string]interface{}{
+			"allow_unknown_severity":  p.GetAllowUnknownSeverity(),
+			"created_at":              timeToString(p.GetCreatedAt()),
+			"description":             p.GetDescription(),
+			"min_severity":            p.GetMinSeverity(),
+			"name":                    p.GetName(),
+			"on_violation_quarantine": p.GetOnViolationQuarantine(),
+			"package_query_string":    p.GetPackageQueryString(),
+			"slug_perm":               p.GetSlugPerm(),
+			"updated_at":              timeToString(p.GetUpdatedAt()),
+		}
+	}
+
+	return policyList
+}
+
+func flattenDenyPolicies(policies []cloudsmith.PackageDenyPolicy) []interface{} {
+	policyList := make([]interface{}, len(policies))
+
+	for i, p := range policies {
+		policyList[i] = map[string]interface{}{
+			"action":               p.GetAction(),
+			"created_at":           timeToString(p.GetCreatedAt()),
+			"description":          p.GetDescription(),
+			"enabled":              p.GetEnabled(),
+			"name":                 p.GetName(),
+			"package_query_string": p.GetPackageQueryString(),
+			"slug_perm":            p.GetSlugPerm(),
+			"status":               p.GetStatus(),
+			"updated_at":           timeToString(p.GetUpdatedAt()),
+		}
+	}
+
+	return policyList
+}
+
+// retrieveDenyPolicies returns every package deny policy configured for a
+// namespace.
+func retrieveDenyPolicies(pc *providerConfig, namespace string) ([]cloudsmith.PackageDenyPolicy, error) {
+	return retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.PackageDenyPolicy, *http.Response, error) {
+		req := pc.APIClient.OrgsApi.OrgsDenyPolicyList(pc.Auth, namespace)
+		req = req.Page(page)
+		req = req.PageSize(pageSize)
+		return pc.APIClient.OrgsApi.OrgsDenyPolicyListExecute(req)
+	})
+}
+
+// retrievePolicies returns every license, vulnerability and package deny
+// policy configured for a namespace.
+func retrievePolicies(pc *providerConfig, namespace string) (
+	[]cloudsmith.OrganizationPackageLicensePolicy,
+	[]cloudsmith.OrganizationPackageVulnerabilityPolicy,
+	[]cloudsmith.PackageDenyPolicy,
+	error,
+) {
+	licensePolicies, err := retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.OrganizationPackageLicensePolicy, *http.Response, error) {
+		req := pc.APIClient.OrgsApi.OrgsLicensePolicyList(pc.Auth, namespace)
+		req = req.Page(page)
+		req = req.PageSize(pageSize)
+		return pc.APIClient.OrgsApi.OrgsLicensePolicyListExecute(req)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	vulnerabilityPolicies, err := retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.OrganizationPackageVulnerabilityPolicy, *http.Response, error) {
+		req := pc.APIClient.OrgsApi.OrgsVulnerabilityPolicyList(pc.Auth, namespace)
+		req = req.Page(page)
+		req = req.PageSize(pageSize)
+		return pc.APIClient.OrgsApi.OrgsVulnerabilityPolicyListExecute(req)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	denyPolicies, err := retrieveDenyPolicies(pc, namespace)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return licensePolicies, vulnerabilityPolicies, denyPolicies, nil
+}
+
+func dataSourcePoliciesRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+
+	licensePolicies, vulnerabilityPolicies, denyPolicies, err := retrievePolicies(pc, namespace)
+	if err != nil {
+		return err
+	}
+
+	d.Set("license_policies", flattenLicensePolicies(licensePolicies))
+	d.Set("vulnerability_policies", flattenVulnerabilityPolicies(vulnerabilityPolicies))
+	d.Set("deny_policies", flattenDenyPolicies(denyPolicies))
+
+	d.SetId(namespace)
+
+	return nil
+}
+
+// dataSourcePolicies returns the schema and implementation for the data source
+// that lists every license, vulnerability and package deny policy configured
+// for a namespace.
+//
+//nolint:funlen
+func dataSourcePolicies() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePoliciesRead,
+
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace for which policies are retrieved.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"license_policies": {
+				Type:        schema.TypeList,
+				Description: "The license policies configured for the namespace.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allow_unknown_licenses": {
+							Type:        schema.TypeBool,
+							Description: "Whether packages with unknown licenses are allowed.",
+							Computed:    true,
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Description: "ISO 8601 timestamp at which the policy was created.",
+							Computed:    true,
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Description: "Description of the policy.",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "A descriptive name for the policy.",
+							Computed:    true,
+						},
+						"on_violation_quarantine": {
+							Type:        schema.TypeBool,
+							Description: "Whether packages violating the policy are quarantined.",
+							Computed:    true,
+						},
+						"package_query_string": {
+							Type:        schema.TypeString,
+							Description: "The query used to select the packages the policy applies to.",
+							Computed:    true,
+						},
+						"slug_perm": {
+							Type:        schema.TypeString,
+							Description: "The slug_perm immutably identifies the policy.",
+							Computed:    true,
+						},
+						"spdx_identifiers": {
+							Type:        schema.TypeList,
+							Description: "The licenses to deny, expressed as SPDX identifiers.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"updated_at": {
+							Type:        schema.TypeString,
+							Description: "ISO 8601 timestamp at which the policy was last updated.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"vulnerability_policies": {
+				Type:        schema.TypeList,
+				Description: "The vulnerability policies configured for the namespace.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allow_unknown_severity": {
+							Type:        schema.TypeBool,
+							Description: "Whether vulnerabilities of unknown severity are allowed.",
+							Computed:    true,
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Description: "ISO 8601 timestamp at which the policy was created.",
+							Computed:    true,
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Description: "Description of the policy.",
+							Computed:    true,
+						},
+						"min_severity": {
+							Type:        schema.TypeString,
+							Description: "The minimum severity at which the policy is violated.",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "A descriptive name for the policy.",
+							Computed:    true,
+						},
+						"on_violation_quarantine": {
+							Type:        schema.TypeBool,
+							Description: "Whether packages violating the policy are quarantined.",
+							Computed:    true,
+						},
+						"package_query_string": {
+							Type:        schema.TypeString,
+							Description: "The query used to select the packages the policy applies to.",
+							Computed:    true,
+						},
+						"slug_perm": {
+							Type:        schema.TypeString,
+							Description: "The slug_perm immutably identifies the policy.",
+							Computed:    true,
+						},
+						"updated_at": {
+							Type:        schema.TypeString,
+							Description: "ISO 8601 timestamp at which the policy was last updated.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"deny_policies": {
+				Type:        schema.TypeList,
+				Description: "The package deny policies configured for the namespace.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"action": {
+							Type:        schema.TypeString,
+							Description: "The action taken when a package matches the policy.",
+							Computed:    true,
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Description: "ISO 8601 timestamp at which the policy was created.",
+							Computed:    true,
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Description: "Description of the policy.",
+							Computed:    true,
+						},
+						"enabled": {
+							Type:        schema.TypeBool,
+							Description: "Whether the policy is enabled.",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "A descriptive name for the policy.",
+							Computed:    true,
+						},
+						"package_query_string": {
+							Type:        schema.TypeString,
+							Description: "The query used to match the packages to be blocked.",
+							Computed:    true,
+						},
+						"slug_perm": {
+							Type:        schema.TypeString,
+							Description: "The slug_perm immutably identifies the policy.",
+							Computed:    true,
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Description: "The status of the policy.",
+							Computed:    true,
+						},
+						"updated_at": {
+							Type:        schema.TypeString,
+							Description: "ISO 8601 timestamp at which the policy was last updated.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
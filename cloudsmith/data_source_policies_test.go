@@ -0,0 +1,71 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccPolicies_basic creates one policy of each kind and verifies that they
+// are all returned by the policies data source.
+func TestAccPolicies_basic(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPoliciesConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.cloudsmith_policies.test", "license_policies.*", map[string]string{
+						"name": "TF Test Policies License",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.cloudsmith_policies.test", "vulnerability_policies.*", map[string]string{
+						"name":         "TF Test Policies Vulnerability",
+						"min_severity": "High",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.cloudsmith_policies.test", "deny_policies.*", map[string]string{
+						"name":                 "tf-test-policies-deny",
+						"package_query_string": "name:tf-test-policies",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccPoliciesConfig() string {
+	return fmt.Sprintf(`
+resource "cloudsmith_license_policy" "test" {
+	name             = "TF Test Policies License"
+	spdx_identifiers = ["Apache-1.0"]
+	organization     = "%[1]s"
+}
+
+resource "cloudsmith_vulnerability_policy" "test" {
+	name         = "TF Test Policies Vulnerability"
+	min_severity = "High"
+	organization = "%[1]s"
+}
+
+resource "cloudsmith_package_deny_policy" "test" {
+	name          = "tf-test-policies-deny"
+	package_query = "name:tf-test-policies"
+	namespace     = "%[1]s"
+}
+
+data "cloudsmith_policies" "test" {
+	namespace = "%[1]s"
+
+	depends_on = [
+		cloudsmith_license_policy.test,
+		cloudsmith_vulnerability_policy.test,
+		cloudsmith_package_deny_policy.test,
+	]
+}
+`, os.Getenv("CLOUDSMITH_NAMESPACE"))
+}
@@ -0,0 +1,103 @@
+package cloudsmith
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/samber/lo"
+)
+
+// flattenRateCheck converts the rate limit of an API resource to a map that
+// can be stored in TF state.
+func flattenRateCheck(resource string, rate cloudsmith.RateCheck) map[string]interface{} {
+	return map[string]interface{}{
+		"interval":  rate.GetInterval(),
+		"limit":     int(rate.GetLimit()),
+		"remaining": int(rate.GetRemaining()),
+		"reset_at":  rate.GetResetIso8601(),
+		"resource":  resource,
+		"throttled": rate.GetThrottled(),
+	}
+}
+
+func dataSourceRateLimitsRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	req := pc.APIClient.RatesApi.RatesLimitsList(pc.Auth)
+	rates, _, err := pc.APIClient.RatesApi.RatesLimitsListExecute(req)
+	if err != nil {
+		return fmt.Errorf("error retrieving rate limits: %w", err)
+	}
+
+	resources := rates.GetResources()
+	names := lo.Keys(resources)
+	sort.Strings(names)
+
+	d.Set("limits", lo.Map(names, func(name string, _ int) interface{} {
+		return flattenRateCheck(name, resources[name])
+	}))
+	d.Set("throttled", lo.SomeBy(lo.Values(resources), func(rate cloudsmith.RateCheck) bool {
+		return rate.GetThrottled()
+	}))
+
+	d.SetId("rate_limits")
+
+	return nil
+}
+
+// dataSourceRateLimits returns the schema and implementation for the data
+// source that reports the API rate limits of the authenticated user.
+func dataSourceRateLimits() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRateLimitsRead,
+
+		Schema: map[string]*schema.Schema{
+			"limits": {
+				Type:        schema.TypeList,
+				Description: "The rate limits of each API resource.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"interval": {
+							Type:        schema.TypeFloat,
+							Description: "The suggested number of seconds to wait between requests to stay within the limit.",
+							Computed:    true,
+						},
+						"limit": {
+							Type:        schema.TypeInt,
+							Description: "The maximum number of requests permitted per window.",
+							Computed:    true,
+						},
+						"remaining": {
+							Type:        schema.TypeInt,
+							Description: "The number of requests remaining in the current window.",
+							Computed:    true,
+						},
+						"reset_at": {
+							Type:        schema.TypeString,
+							Description: "ISO 8601 timestamp at which the current window resets.",
+							Computed:    true,
+						},
+						"resource": {
+							Type:        schema.TypeString,
+							Description: "The API resource to which the limit applies.",
+							Computed:    true,
+						},
+						"throttled": {
+							Type:        schema.TypeBool,
+							Description: "If true, requests to the resource are currently being throttled.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"throttled": {
+				Type:        schema.TypeBool,
+				Description: "If true, requests to at least one API resource are currently being throttled.",
+				Computed:    true,
+			},
+		},
+	}
+}
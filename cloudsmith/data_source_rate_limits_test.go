@@ -0,0 +1,57 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestFlattenRateCheck(t *testing.T) {
+	t.Parallel()
+
+	rate := cloudsmith.RateCheck{
+		Interval:     cloudsmith.PtrFloat64(3.6),
+		Limit:        cloudsmith.PtrInt64(1000),
+		Remaining:    cloudsmith.PtrInt64(840),
+		ResetIso8601: cloudsmith.PtrString("2023-01-02T15:04:05Z"),
+		Throttled:    cloudsmith.PtrBool(false),
+	}
+
+	want := map[string]interface{}{
+		"interval":  3.6,
+		"limit":     1000,
+		"remaining": 840,
+		"reset_at":  "2023-01-02T15:04:05Z",
+		"resource":  "core",
+		"throttled": false,
+	}
+	if got := flattenRateCheck("core", rate); !reflect.DeepEqual(got, want) {
+		t.Errorf("flattenRateCheck() = %v, want %v", got, want)
+	}
+}
+
+func TestAccRateLimits_basic(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRateLimitsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.cloudsmith_rate_limits.test", "limits.0.resource"),
+					resource.TestCheckResourceAttrSet("data.cloudsmith_rate_limits.test", "limits.0.limit"),
+					resource.TestCheckResourceAttrSet("data.cloudsmith_rate_limits.test", "throttled"),
+				),
+			},
+		},
+	})
+}
+
+const testAccRateLimitsConfig = `
+data "cloudsmith_rate_limits" "test" {}
+`
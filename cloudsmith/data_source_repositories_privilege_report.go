@@ -0,0 +1,154 @@
+package cloudsmith
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
+)
+
+// Types of principal to which repository privileges can be granted.
+const (
+	principalTypeService = "service"
+	principalTypeTeam    = "team"
+	principalTypeUser    = "user"
+)
+
+// flattenPrivilegeReportEntry converts a privilege of a repository to a row of
+// the privilege report that can be stored in TF state.
+func flattenPrivilegeReportEntry(repository string, p cloudsmith.RepositoryPrivilegeDict) map[string]interface{} {
+	entry := map[string]interface{}{
+		"privilege":  p.GetPrivilege(),
+		"repository": repository,
+	}
+
+	switch {
+	case p.HasService():
+		entry["principal"] = p.GetService()
+		entry["principal_type"] = principalTypeService
+	case p.HasTeam():
+		entry["principal"] = p.GetTeam()
+		entry["principal_type"] = principalTypeTeam
+	default:
+		entry["principal"] = p.GetUser()
+		entry["principal_type"] = principalTypeUser
+	}
+
+	return entry
+}
+
+func dataSourceRepositoriesPrivilegeReportRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+
+	repositories, err := retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.Repository, *http.Response, error) {
+		return pc.Repos.List(namespace, page, pageSize)
+	})
+	if err != nil {
+		return fmt.Errorf("error retrieving repositories: %w", err)
+	}
+
+	if selected := expandStrings(d, "repositories"); len(selected) > 0 {
+		repositories = lo.Filter(repositories, func(r cloudsmith.Repository, _ int) bool {
+			return lo.Contains(selected, r.GetSlug())
+		})
+	}
+
+	report := []map[string]interface{}{}
+
+	// Repositories are read one at a time, rather than concurrently, so that a
+	// large namespace doesn't exhaust the rate limit; throttled requests are
+	// retried by the transport.
+	for _, repository := range repositories {
+		privileges, resp, err := retrieveRepositoryPrivileges(pc, namespace, repository.GetSlugPerm())
+		if err != nil {
+			// the repository was deleted since it was listed
+			if is404(resp) {
+				continue
+			}
+			return fmt.Errorf("error retrieving privileges of %s: %w", repository.GetSlug(), err)
+		}
+
+		for _, privilege := range privileges {
+			report = append(report, flattenPrivilegeReportEntry(repository.GetSlug(), privilege))
+		}
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		for _, key := range []string{"repository", "principal_type", "principal"} {
+			if report[i][key] != report[j][key] {
+				return report[i][key].(string) < report[j][key].(string)
+			}
+		}
+		return false
+	})
+
+	d.Set("privileges", report)
+
+	d.SetId(namespace)
+
+	return nil
+}
+
+// dataSourceRepositoriesPrivilegeReport returns the schema and implementation
+// for the data source that flattens the privileges of every repository in a
+// namespace into (repository, principal, privilege) rows for access reviews.
+//
+//nolint:funlen
+func dataSourceRepositoriesPrivilegeReport() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRepositoriesPrivilegeReportRead,
+
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace (organization) whose repositories are reported on.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"privileges": {
+				Type:        schema.TypeList,
+				Description: "The privileges granted on the repositories.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"principal": {
+							Type:        schema.TypeString,
+							Description: "The slug of the service, team or user granted the privilege.",
+							Computed:    true,
+						},
+						"principal_type": {
+							Type:        schema.TypeString,
+							Description: "The type of the principal: service, team or user.",
+							Computed:    true,
+						},
+						"privilege": {
+							Type:        schema.TypeString,
+							Description: "The privilege level: Admin, Write or Read.",
+							Computed:    true,
+						},
+						"repository": {
+							Type:        schema.TypeString,
+							Description: "The slug of the repository.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"repositories": {
+				Type:        schema.TypeSet,
+				Description: "The slugs of the repositories to report on. If omitted, all repositories in the namespace are included.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+		},
+	}
+}
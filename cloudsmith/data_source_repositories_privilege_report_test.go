@@ -0,0 +1,133 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// TestRepositoriesPrivilegeReport reads the report against a fake service,
+// with enough privileges on one repository to span several pages.
+func TestRepositoriesPrivilegeReport(t *testing.T) {
+	t.Parallel()
+
+	privilege := func(principalType, principal, level string) cloudsmith.RepositoryPrivilegeDict {
+		p := cloudsmith.RepositoryPrivilegeDict{Privilege: level}
+		switch principalType {
+		case principalTypeService:
+			p.SetService(principal)
+		case principalTypeTeam:
+			p.SetTeam(principal)
+		default:
+			p.SetUser(principal)
+		}
+		return p
+	}
+
+	users := make([]cloudsmith.RepositoryPrivilegeDict, 1500)
+	for i := range users {
+		users[i] = privilege(principalTypeUser, fmt.Sprintf("user-%04d", i), "Read")
+	}
+
+	pc := &providerConfig{
+		Repos: &fakeReposService{
+			repositories: map[string][]cloudsmith.Repository{
+				"my-org": {
+					{Slug: cloudsmith.PtrString("web"), SlugPerm: cloudsmith.PtrString("AbCdEfGhIjKl")},
+					{Slug: cloudsmith.PtrString("api"), SlugPerm: cloudsmith.PtrString("MnOpQrStUvWx")},
+				},
+			},
+			privileges: map[string][]cloudsmith.RepositoryPrivilegeDict{
+				"my-org/api": {
+					privilege(principalTypeUser, "jane", "Admin"),
+					privilege(principalTypeTeam, "ops", "Write"),
+					privilege(principalTypeService, "ci", "Read"),
+				},
+				"my-org/web": users,
+			},
+		},
+	}
+
+	r := dataSourceRepositoriesPrivilegeReport()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"namespace": "my-org",
+	})
+
+	if err := dataSourceRepositoriesPrivilegeReportRead(d, pc); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	report := d.Get("privileges").([]interface{})
+	if len(report) != 1503 {
+		t.Fatalf("expected 1503 privileges, got %d", len(report))
+	}
+
+	want := []interface{}{
+		map[string]interface{}{"principal": "ci", "principal_type": "service", "privilege": "Read", "repository": "api"},
+		map[string]interface{}{"principal": "ops", "principal_type": "team", "privilege": "Write", "repository": "api"},
+		map[string]interface{}{"principal": "jane", "principal_type": "user", "privilege": "Admin", "repository": "api"},
+		map[string]interface{}{"principal": "user-0000", "principal_type": "user", "privilege": "Read", "repository": "web"},
+	}
+	if got := report[:4]; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the report to be sorted by repository and principal, got %v", got)
+	}
+}
+
+// TestAccRepositoriesPrivilegeReport_basic grants a team access to a
+// repository and verifies that it appears in the report.
+func TestAccRepositoriesPrivilegeReport_basic(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRepositoriesPrivilegeReportConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.cloudsmith_repositories_privilege_report.test", "privileges.*", map[string]string{
+						"principal":      "tf-test-privilege-report",
+						"principal_type": "team",
+						"privilege":      "Write",
+						"repository":     "terraform-acc-test-privilege-report",
+					}),
+				),
+			},
+		},
+	})
+}
+
+var testAccRepositoriesPrivilegeReportConfig = fmt.Sprintf(`
+resource "cloudsmith_repository" "test" {
+	name      = "terraform-acc-test-privilege-report"
+	namespace = "%[1]s"
+}
+
+resource "cloudsmith_team" "test" {
+	organization = "%[1]s"
+	name         = "tf-test-privilege-report"
+}
+
+resource "cloudsmith_repository_privileges" "test" {
+	organization = cloudsmith_repository.test.namespace
+	repository   = cloudsmith_repository.test.slug
+
+	team {
+		privilege = "Write"
+		slug      = cloudsmith_team.test.slug
+	}
+}
+
+data "cloudsmith_repositories_privilege_report" "test" {
+	namespace    = "%[1]s"
+	repositories = [cloudsmith_repository.test.slug]
+
+	depends_on = [cloudsmith_repository_privileges.test]
+}
+`, os.Getenv("CLOUDSMITH_NAMESPACE"))
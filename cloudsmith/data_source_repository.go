@@ -31,6 +31,8 @@ func dataSourceRepositoryRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("docker_refresh_tokens_enabled", repository.GetDockerRefreshTokensEnabled())
 	d.Set("index_files", repository.GetIndexFiles())
 	d.Set("is_open_source", repository.GetIsOpenSource())
+	d.Set("open_source_license", repository.GetOpenSourceLicense())
+	d.Set("open_source_project_url", repository.GetOpenSourceProjectUrl())
 	d.Set("is_private", repository.GetIsPrivate())
 	d.Set("is_public", repository.GetIsPublic())
 	d.Set("move_own", repository.GetMoveOwn())
@@ -207,6 +209,16 @@ func dataSourceRepository() *schema.Resource {
 				Description: "API endpoint where data about this namespace can be retrieved.",
 				Computed:    true,
 			},
+			"open_source_license": {
+				Type:        schema.TypeString,
+				Description: "The SPDX identifier of the open source license of the repository's contents.",
+				Computed:    true,
+			},
+			"open_source_project_url": {
+				Type:        schema.TypeString,
+				Description: "The URL of the open source project the repository's contents belong to.",
+				Computed:    true,
+			},
 			"proxy_npmjs": {
 				Type: schema.TypeBool,
 				Description: "If checked, Npm packages that are not in the repository when requested by clients will " +
@@ -252,7 +264,8 @@ func dataSourceRepository() *schema.Resource {
 				Type: schema.TypeString,
 				Description: "The repository type changes how it is accessed and billed. Private repositories " +
 					"can only be used on paid plans, but are visible only to you or authorised delegates. Public " +
-					"repositories are free to use on all plans and visible to all Cloudsmith users.",
+					"repositories are free to use on all plans and visible to all Cloudsmith users. Open-Source " +
+					"repositories are public repositories of open source projects.",
 				Computed: true,
 			},
 			"resync_own": {
@@ -0,0 +1,149 @@
+package cloudsmith
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
+)
+
+// matchSAMLGroupSyncs returns the group syncs which a user whose IdP assertion
+// contains the given key and value would be synced by, sorted by team and role.
+func matchSAMLGroupSyncs(syncs []cloudsmith.OrganizationGroupSync, idpKey, idpValue string) []cloudsmith.OrganizationGroupSync {
+	matches := lo.Filter(syncs, func(s cloudsmith.OrganizationGroupSync, _ int) bool {
+		return s.GetIdpKey() == idpKey && s.GetIdpValue() == idpValue
+	})
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].GetTeam() != matches[j].GetTeam() {
+			return matches[i].GetTeam() < matches[j].GetTeam()
+		}
+		return matches[i].GetRole() < matches[j].GetRole()
+	})
+
+	return matches
+}
+
+func dataSourceSAMLGroupSyncPreviewRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	organization := requiredString(d, "organization")
+	idpKey := requiredString(d, "idp_key")
+	idpValue := requiredString(d, "idp_value")
+
+	syncs, err := retrieveSAMLSyncListPages(pc, organization, -1, -1)
+	if err != nil {
+		return fmt.Errorf("error retrieving SAML group syncs: %w", err)
+	}
+
+	teams, err := retrieveTeams(pc, organization)
+	if err != nil {
+		return fmt.Errorf("error retrieving teams: %w", err)
+	}
+
+	// group syncs may refer to teams by slug or slug_perm
+	existingTeams := map[string]bool{}
+	for _, team := range teams {
+		existingTeams[team.GetSlug()] = true
+		existingTeams[team.GetSlugPerm()] = true
+	}
+
+	status, _, err := pc.SAML.GroupSyncStatus(organization)
+	if err != nil {
+		return fmt.Errorf("error retrieving SAML group sync status: %w", err)
+	}
+
+	matches := matchSAMLGroupSyncs(syncs, idpKey, idpValue)
+
+	d.Set("enabled", status.GetSamlGroupSyncStatus())
+	d.Set("matches", lo.Map(matches, func(s cloudsmith.OrganizationGroupSync, _ int) interface{} {
+		return map[string]interface{}{
+			"role":        s.GetRole(),
+			"slug_perm":   s.GetSlugPerm(),
+			"team":        s.GetTeam(),
+			"team_exists": existingTeams[s.GetTeam()],
+		}
+	}))
+	d.Set("teams", lo.Uniq(lo.Map(matches, func(s cloudsmith.OrganizationGroupSync, _ int) string {
+		return s.GetTeam()
+	})))
+
+	d.SetId(fmt.Sprintf("%s.%s.%s", organization, idpKey, idpValue))
+
+	return nil
+}
+
+// dataSourceSAMLGroupSyncPreview returns the schema and implementation for the
+// data source that reports which SAML group syncs would apply to a user with a
+// given IdP key and value, so mapping changes can be checked before applying.
+//
+//nolint:funlen
+func dataSourceSAMLGroupSyncPreview() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceSAMLGroupSyncPreviewRead,
+
+		Schema: map[string]*schema.Schema{
+			"enabled": {
+				Type:        schema.TypeBool,
+				Description: "If true, SAML group sync is enabled for the organization, so the matching group syncs are applied when users log in.",
+				Computed:    true,
+			},
+			"idp_key": {
+				Type:         schema.TypeString,
+				Description:  "The attribute key from the identity provider.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"idp_value": {
+				Type:         schema.TypeString,
+				Description:  "The attribute value from the identity provider.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"matches": {
+				Type:        schema.TypeList,
+				Description: "The group syncs matching the IdP key and value.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role": {
+							Type:        schema.TypeString,
+							Description: "The role the user would be given in the team.",
+							Computed:    true,
+						},
+						"slug_perm": {
+							Type:        schema.TypeString,
+							Description: "The slug_perm of the group sync.",
+							Computed:    true,
+						},
+						"team": {
+							Type:        schema.TypeString,
+							Description: "The team the user would be added to.",
+							Computed:    true,
+						},
+						"team_exists": {
+							Type:        schema.TypeBool,
+							Description: "If false, the team of the group sync no longer exists in the organization.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"organization": {
+				Type:         schema.TypeString,
+				Description:  "Organization slug for the SAML group syncs.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"teams": {
+				Type:        schema.TypeList,
+				Description: "The teams the user would be added to.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
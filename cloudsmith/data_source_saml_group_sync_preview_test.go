@@ -0,0 +1,89 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/samber/lo"
+)
+
+func TestMatchSAMLGroupSyncs(t *testing.T) {
+	t.Parallel()
+
+	syncs := []cloudsmith.OrganizationGroupSync{
+		{IdpKey: "groups", IdpValue: "ad-developers", Team: "developers", Role: cloudsmith.PtrString("Member")},
+		{IdpKey: "groups", IdpValue: "ad-ops", Team: "operations", Role: cloudsmith.PtrString("Member")},
+		{IdpKey: "groups", IdpValue: "ad-developers", Team: "builders", Role: cloudsmith.PtrString("Manager")},
+		{IdpKey: "department", IdpValue: "ad-developers", Team: "engineering", Role: cloudsmith.PtrString("Member")},
+	}
+
+	got := lo.Map(matchSAMLGroupSyncs(syncs, "groups", "ad-developers"), func(s cloudsmith.OrganizationGroupSync, _ int) string {
+		return s.GetTeam()
+	})
+	want := []string{"builders", "developers"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchSAMLGroupSyncs() teams = %v, want %v", got, want)
+	}
+
+	if got := matchSAMLGroupSyncs(syncs, "groups", "AD-DEVELOPERS"); len(got) != 0 {
+		t.Errorf("matchSAMLGroupSyncs() matched %d group syncs with a value of a different case", len(got))
+	}
+}
+
+// TestAccSAMLGroupSyncPreview_basic creates a group sync and verifies that it
+// is reported for its IdP key and value, and not for others.
+func TestAccSAMLGroupSyncPreview_basic(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSAMLGroupSyncPreviewConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cloudsmith_saml_group_sync_preview.match", "matches.#", "1"),
+					resource.TestCheckResourceAttr("data.cloudsmith_saml_group_sync_preview.match", "matches.0.role", "Member"),
+					resource.TestCheckResourceAttr("data.cloudsmith_saml_group_sync_preview.match", "matches.0.team_exists", "true"),
+					resource.TestCheckResourceAttr("data.cloudsmith_saml_group_sync_preview.no_match", "matches.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+var testAccSAMLGroupSyncPreviewConfig = fmt.Sprintf(`
+resource "cloudsmith_team" "test" {
+	organization = "%[1]s"
+	name         = "tf-test-saml-preview"
+}
+
+resource "cloudsmith_saml_group_sync" "test" {
+	organization = "%[1]s"
+	idp_key      = "groups"
+	idp_value    = "tf-test-saml-preview"
+	role         = "Member"
+	team         = cloudsmith_team.test.slug
+}
+
+data "cloudsmith_saml_group_sync_preview" "match" {
+	organization = "%[1]s"
+	idp_key      = "groups"
+	idp_value    = "tf-test-saml-preview"
+
+	depends_on = [cloudsmith_saml_group_sync.test]
+}
+
+data "cloudsmith_saml_group_sync_preview" "no_match" {
+	organization = "%[1]s"
+	idp_key      = "groups"
+	idp_value    = "tf-test-saml-preview-other"
+
+	depends_on = [cloudsmith_saml_group_sync.test]
+}
+`, os.Getenv("CLOUDSMITH_NAMESPACE"))
@@ -0,0 +1,229 @@
+package cloudsmith
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
+)
+
+// securityPosture is the document exported by the cloudsmith_security_posture
+// data source. Every list in it is sorted so that the JSON encoding only
+// changes when the underlying configuration does.
+type securityPosture struct {
+	Namespace            string                      `json:"namespace"`
+	SamlGroupSyncEnabled bool                        `json:"saml_group_sync_enabled"`
+	Policies             securityPosturePolicies     `json:"policies"`
+	Repositories         []securityPostureRepository `json:"repositories"`
+}
+
+type securityPosturePolicies struct {
+	DenyCount                    int `json:"deny_count"`
+	DenyEnabledCount             int `json:"deny_enabled_count"`
+	LicenseCount                 int `json:"license_count"`
+	LicenseQuarantineCount       int `json:"license_quarantine_count"`
+	VulnerabilityCount           int `json:"vulnerability_count"`
+	VulnerabilityQuarantineCount int `json:"vulnerability_quarantine_count"`
+}
+
+type securityPostureRepository struct {
+	Slug           string                     `json:"slug"`
+	RepositoryType string                     `json:"repository_type"`
+	GeoIpRules     *securityPostureGeoIpRules `json:"geo_ip_rules"`
+}
+
+type securityPostureGeoIpRules struct {
+	CidrAllow        []string `json:"cidr_allow"`
+	CidrDeny         []string `json:"cidr_deny"`
+	CountryCodeAllow []string `json:"country_code_allow"`
+	CountryCodeDeny  []string `json:"country_code_deny"`
+}
+
+// sortedGeoIpRules normalizes and sorts a list of Geo/IP rules.
+func sortedGeoIpRules(rules []string, normalize func(string) string) []string {
+	out := lo.Map(rules, normalizeGeoIpRule(normalize))
+	sort.Strings(out)
+	return out
+}
+
+// retrieveSecurityPostureGeoIpRules returns the Geo/IP rules of a repository,
+// or nil if it has none because Geo/IP rules aren't enabled for it.
+func retrieveSecurityPostureGeoIpRules(pc *providerConfig, namespace, repository string) (*securityPostureGeoIpRules, error) {
+	geoIpRules, resp, err := pc.GeoIP.Read(namespace, repository)
+	if err != nil {
+		if is404(resp) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error retrieving Geo/IP rules for repository %s: %w", repository, err)
+	}
+
+	cidr := geoIpRules.GetCidr()
+	countryCode := geoIpRules.GetCountryCode()
+
+	return &securityPostureGeoIpRules{
+		CidrAllow:        sortedGeoIpRules(cidr.GetAllow(), normalizeCidr),
+		CidrDeny:         sortedGeoIpRules(cidr.GetDeny(), normalizeCidr),
+		CountryCodeAllow: sortedGeoIpRules(countryCode.GetAllow(), normalizeCountryCode),
+		CountryCodeDeny:  sortedGeoIpRules(countryCode.GetDeny(), normalizeCountryCode),
+	}, nil
+}
+
+// summarizePolicies counts the policies configured for a namespace.
+func summarizePolicies(
+	licensePolicies []cloudsmith.OrganizationPackageLicensePolicy,
+	vulnerabilityPolicies []cloudsmith.OrganizationPackageVulnerabilityPolicy,
+	denyPolicies []cloudsmith.PackageDenyPolicy,
+) securityPosturePolicies {
+	return securityPosturePolicies{
+		DenyCount: len(denyPolicies),
+		DenyEnabledCount: lo.CountBy(denyPolicies, func(p cloudsmith.PackageDenyPolicy) bool {
+			return p.GetEnabled()
+		}),
+		LicenseCount: len(licensePolicies),
+		LicenseQuarantineCount: lo.CountBy(licensePolicies, func(p cloudsmith.OrganizationPackageLicensePolicy) bool {
+			return p.GetOnViolationQuarantine()
+		}),
+		VulnerabilityCount: len(vulnerabilityPolicies),
+		VulnerabilityQuarantineCount: lo.CountBy(vulnerabilityPolicies, func(p cloudsmith.OrganizationPackageVulnerabilityPolicy) bool {
+			return p.GetOnViolationQuarantine()
+		}),
+	}
+}
+
+func dataSourceSecurityPostureRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+
+	licensePolicies, vulnerabilityPolicies, denyPolicies, err := retrievePolicies(pc, namespace)
+	if err != nil {
+		return fmt.Errorf("error retrieving policies: %w", err)
+	}
+
+	status, _, err := pc.SAML.GroupSyncStatus(namespace)
+	if err != nil {
+		return fmt.Errorf("error retrieving SAML group sync status: %w", err)
+	}
+
+	repositories, err := retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.Repository, *http.Response, error) {
+		return pc.Repos.List(namespace, page, pageSize)
+	})
+	if err != nil {
+		return fmt.Errorf("error retrieving repositories: %w", err)
+	}
+
+	// only look up the Geo/IP rules of the requested repositories, as it takes
+	// one request per repository
+	if selected := expandStrings(d, "repositories"); len(selected) > 0 {
+		repositories = lo.Filter(repositories, func(r cloudsmith.Repository, _ int) bool {
+			return lo.Contains(selected, r.GetSlug())
+		})
+	}
+
+	sort.Slice(repositories, func(i, j int) bool {
+		return repositories[i].GetSlug() < repositories[j].GetSlug()
+	})
+
+	posture := securityPosture{
+		Namespace:            namespace,
+		SamlGroupSyncEnabled: status.GetSamlGroupSyncStatus(),
+		Policies:             summarizePolicies(licensePolicies, vulnerabilityPolicies, denyPolicies),
+		Repositories:         make([]securityPostureRepository, 0, len(repositories)),
+	}
+
+	for _, repository := range repositories {
+		geoIpRules, err := retrieveSecurityPostureGeoIpRules(pc, namespace, repository.GetSlug())
+		if err != nil {
+			return err
+		}
+
+		posture.Repositories = append(posture.Repositories, securityPostureRepository{
+			Slug:           repository.GetSlug(),
+			RepositoryType: repository.GetRepositoryTypeStr(),
+			GeoIpRules:     geoIpRules,
+		})
+	}
+
+	document, err := json.Marshal(posture)
+	if err != nil {
+		return fmt.Errorf("error encoding security posture: %w", err)
+	}
+
+	d.Set("json", string(document))
+	d.Set("saml_group_sync_enabled", posture.SamlGroupSyncEnabled)
+	d.Set("deny_policy_count", posture.Policies.DenyCount)
+	d.Set("license_policy_count", posture.Policies.LicenseCount)
+	d.Set("vulnerability_policy_count", posture.Policies.VulnerabilityCount)
+	d.Set("geo_ip_rules_repositories", lo.FilterMap(posture.Repositories, func(r securityPostureRepository, _ int) (string, bool) {
+		return r.Slug, r.GeoIpRules != nil
+	}))
+
+	d.SetId(namespace)
+
+	return nil
+}
+
+// dataSourceSecurityPosture returns the schema and implementation for the
+// data source that aggregates the security configuration of a namespace into
+// a single normalized JSON document, for checking with policy-as-code tools.
+//
+//nolint:funlen
+func dataSourceSecurityPosture() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceSecurityPostureRead,
+
+		Schema: map[string]*schema.Schema{
+			"deny_policy_count": {
+				Type:        schema.TypeInt,
+				Description: "The number of package deny policies configured for the namespace.",
+				Computed:    true,
+			},
+			"geo_ip_rules_repositories": {
+				Type:        schema.TypeList,
+				Description: "The slugs of the repositories which have Geo/IP rules enabled.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"json": {
+				Type:        schema.TypeString,
+				Description: "The security posture of the namespace as a normalized JSON document.",
+				Computed:    true,
+			},
+			"license_policy_count": {
+				Type:        schema.TypeInt,
+				Description: "The number of license policies configured for the namespace.",
+				Computed:    true,
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace (organization) for which the security posture is retrieved.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repositories": {
+				Type:        schema.TypeSet,
+				Description: "The slugs of the repositories to include. If omitted, all repositories in the namespace are included.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+			"saml_group_sync_enabled": {
+				Type:        schema.TypeBool,
+				Description: "If true, SAML group sync is enabled for the namespace.",
+				Computed:    true,
+			},
+			"vulnerability_policy_count": {
+				Type:        schema.TypeInt,
+				Description: "The number of vulnerability policies configured for the namespace.",
+				Computed:    true,
+			},
+		},
+	}
+}
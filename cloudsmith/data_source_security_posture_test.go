@@ -0,0 +1,104 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestSummarizePolicies(t *testing.T) {
+	t.Parallel()
+
+	got := summarizePolicies(
+		[]cloudsmith.OrganizationPackageLicensePolicy{
+			{OnViolationQuarantine: cloudsmith.PtrBool(true)},
+			{},
+		},
+		[]cloudsmith.OrganizationPackageVulnerabilityPolicy{
+			{OnViolationQuarantine: cloudsmith.PtrBool(false)},
+		},
+		[]cloudsmith.PackageDenyPolicy{
+			{Enabled: cloudsmith.PtrBool(true)},
+			{Enabled: cloudsmith.PtrBool(true)},
+			{Enabled: cloudsmith.PtrBool(false)},
+		},
+	)
+
+	want := securityPosturePolicies{
+		DenyCount:                    3,
+		DenyEnabledCount:             2,
+		LicenseCount:                 2,
+		LicenseQuarantineCount:       1,
+		VulnerabilityCount:           1,
+		VulnerabilityQuarantineCount: 0,
+	}
+	if got != want {
+		t.Errorf("summarizePolicies() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSortedGeoIpRules(t *testing.T) {
+	t.Parallel()
+
+	got := sortedGeoIpRules([]string{" us", "GB", "de "}, normalizeCountryCode)
+	want := []string{"DE", "GB", "US"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sortedGeoIpRules() = %v, want %v", got, want)
+	}
+}
+
+// TestAccSecurityPosture_basic creates a repository with Geo/IP rules and a
+// deny policy and verifies that both are reflected in the security posture.
+func TestAccSecurityPosture_basic(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSecurityPostureConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.cloudsmith_security_posture.test", "json"),
+					resource.TestCheckResourceAttr("data.cloudsmith_security_posture.test", "geo_ip_rules_repositories.#", "1"),
+					resource.TestCheckResourceAttr("data.cloudsmith_security_posture.test", "geo_ip_rules_repositories.0", "terraform-acc-test-security-posture"),
+				),
+			},
+		},
+	})
+}
+
+var testAccSecurityPostureConfig = fmt.Sprintf(`
+resource "cloudsmith_repository" "test" {
+	name      = "terraform-acc-test-security-posture"
+	namespace = "%[1]s"
+}
+
+resource "cloudsmith_repository_geo_ip_rules" "test" {
+	namespace          = cloudsmith_repository.test.namespace
+	repository         = cloudsmith_repository.test.slug
+	cidr_allow         = ["10.0.0.0/24"]
+	country_code_allow = ["GB"]
+}
+
+resource "cloudsmith_package_deny_policy" "test" {
+	name          = "tf-test-security-posture"
+	package_query = "name:tf-test-security-posture"
+	namespace     = "%[1]s"
+}
+
+data "cloudsmith_security_posture" "test" {
+	namespace    = "%[1]s"
+	repositories = [cloudsmith_repository.test.slug]
+
+	depends_on = [
+		cloudsmith_repository_geo_ip_rules.test,
+		cloudsmith_package_deny_policy.test,
+	]
+}
+`, os.Getenv("CLOUDSMITH_NAMESPACE"))
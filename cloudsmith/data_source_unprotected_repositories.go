@@ -0,0 +1,148 @@
+package cloudsmith
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
+)
+
+// Reasons for which a repository is reported as unprotected.
+const (
+	unprotectedGeoIpDisabled = "geo_ip_disabled"
+	unprotectedNoGeoIpRules  = "no_geo_ip_rules"
+)
+
+// unprotectedReason returns why a repository with the given Geo/IP rules (nil
+// if Geo/IP is disabled for it) isn't geo-fenced, or false if it is.
+func unprotectedReason(geoIpRules *securityPostureGeoIpRules) (string, bool) {
+	switch {
+	case geoIpRules == nil:
+		return unprotectedGeoIpDisabled, true
+	case len(geoIpRules.CidrAllow)+len(geoIpRules.CidrDeny)+len(geoIpRules.CountryCodeAllow)+len(geoIpRules.CountryCodeDeny) == 0:
+		return unprotectedNoGeoIpRules, true
+	default:
+		return "", false
+	}
+}
+
+func dataSourceUnprotectedRepositoriesRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+
+	repositories, err := retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.Repository, *http.Response, error) {
+		return pc.Repos.List(namespace, page, pageSize)
+	})
+	if err != nil {
+		return fmt.Errorf("error retrieving repositories: %w", err)
+	}
+
+	if selected := expandStrings(d, "repositories"); len(selected) > 0 {
+		repositories = lo.Filter(repositories, func(r cloudsmith.Repository, _ int) bool {
+			return lo.Contains(selected, r.GetSlug())
+		})
+	}
+
+	sort.Slice(repositories, func(i, j int) bool {
+		return repositories[i].GetSlug() < repositories[j].GetSlug()
+	})
+
+	unprotected := []interface{}{}
+	slugs := []string{}
+
+	for _, repository := range repositories {
+		geoIpRules, err := retrieveSecurityPostureGeoIpRules(pc, namespace, repository.GetSlug())
+		if err != nil {
+			return err
+		}
+
+		reason, ok := unprotectedReason(geoIpRules)
+		if !ok {
+			continue
+		}
+
+		unprotected = append(unprotected, map[string]interface{}{
+			"slug":            repository.GetSlug(),
+			"slug_perm":       repository.GetSlugPerm(),
+			"repository_type": repository.GetRepositoryTypeStr(),
+			"reason":          reason,
+		})
+		slugs = append(slugs, repository.GetSlug())
+	}
+
+	d.Set("unprotected", unprotected)
+	d.Set("slugs", slugs)
+
+	d.SetId(namespace)
+
+	return nil
+}
+
+// dataSourceUnprotectedRepositories returns the schema and implementation for
+// the data source that lists the repositories of a namespace which aren't
+// geo-fenced, either because Geo/IP is disabled or because it has no rules.
+//
+//nolint:funlen
+func dataSourceUnprotectedRepositories() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceUnprotectedRepositoriesRead,
+
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace (organization) whose repositories are checked.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repositories": {
+				Type:        schema.TypeSet,
+				Description: "The slugs of the repositories to check. If omitted, all repositories in the namespace are checked.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+			"slugs": {
+				Type:        schema.TypeList,
+				Description: "The slugs of the unprotected repositories.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"unprotected": {
+				Type:        schema.TypeList,
+				Description: "The repositories which aren't protected by Geo/IP rules.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"reason": {
+							Type:        schema.TypeString,
+							Description: "Why the repository is unprotected: geo_ip_disabled or no_geo_ip_rules.",
+							Computed:    true,
+						},
+						"repository_type": {
+							Type:        schema.TypeString,
+							Description: "The type of the repository, e.g. Public or Private.",
+							Computed:    true,
+						},
+						"slug": {
+							Type:        schema.TypeString,
+							Description: "The slug of the repository.",
+							Computed:    true,
+						},
+						"slug_perm": {
+							Type:        schema.TypeString,
+							Description: "The slug_perm of the repository.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
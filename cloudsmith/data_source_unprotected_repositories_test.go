@@ -0,0 +1,84 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnprotectedReason(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		geoIpRules *securityPostureGeoIpRules
+		reason     string
+		ok         bool
+	}{
+		{"disabled", nil, unprotectedGeoIpDisabled, true},
+		{"no rules", &securityPostureGeoIpRules{}, unprotectedNoGeoIpRules, true},
+		{"cidr", &securityPostureGeoIpRules{CidrAllow: []string{"10.0.0.0/24"}}, "", false},
+		{"country code", &securityPostureGeoIpRules{CountryCodeDeny: []string{"GB"}}, "", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			reason, ok := unprotectedReason(tc.geoIpRules)
+			if reason != tc.reason || ok != tc.ok {
+				t.Errorf("unprotectedReason() = (%q, %t), want (%q, %t)", reason, ok, tc.reason, tc.ok)
+			}
+		})
+	}
+}
+
+// TestAccUnprotectedRepositories_basic creates one repository with Geo/IP
+// rules and one without, and verifies that only the latter is reported.
+func TestAccUnprotectedRepositories_basic(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUnprotectedRepositoriesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cloudsmith_unprotected_repositories.test", "slugs.#", "1"),
+					resource.TestCheckResourceAttr("data.cloudsmith_unprotected_repositories.test", "slugs.0", "terraform-acc-test-unprotected"),
+					resource.TestCheckResourceAttr("data.cloudsmith_unprotected_repositories.test", "unprotected.0.reason", unprotectedGeoIpDisabled),
+				),
+			},
+		},
+	})
+}
+
+var testAccUnprotectedRepositoriesConfig = fmt.Sprintf(`
+resource "cloudsmith_repository" "protected" {
+	name      = "terraform-acc-test-protected"
+	namespace = "%[1]s"
+}
+
+resource "cloudsmith_repository" "unprotected" {
+	name      = "terraform-acc-test-unprotected"
+	namespace = "%[1]s"
+}
+
+resource "cloudsmith_repository_geo_ip_rules" "test" {
+	namespace  = cloudsmith_repository.protected.namespace
+	repository = cloudsmith_repository.protected.slug
+	cidr_allow = ["10.0.0.0/24"]
+}
+
+data "cloudsmith_unprotected_repositories" "test" {
+	namespace    = "%[1]s"
+	repositories = [cloudsmith_repository.protected.slug, cloudsmith_repository.unprotected.slug]
+
+	depends_on = [cloudsmith_repository_geo_ip_rules.test]
+}
+`, os.Getenv("CLOUDSMITH_NAMESPACE"))
@@ -0,0 +1,193 @@
+package cloudsmith
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// usageDatePattern matches the plain UTC dates accepted by the metrics
+// endpoints as an alternative to full timestamps, e.g. "2024-12-31".
+var usageDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// validateUsageDate accepts either a plain UTC date or an RFC 3339 timestamp.
+var validateUsageDate = validation.Any(
+	validation.StringMatch(usageDatePattern, "must be a date in YYYY-MM-DD format"),
+	validation.IsRFC3339Time,
+)
+
+// retrieveEntitlementUsage returns the usage metrics of a single entitlement
+// token. The metrics endpoint only reports totals across all of the tokens it
+// is asked about, so it must be called once per token to get a breakdown.
+func retrieveEntitlementUsage(pc *providerConfig, namespace, repository, slugPerm string, start, finish *string) (*cloudsmith.CommonMetrics, error) {
+	req := pc.APIClient.MetricsApi.MetricsEntitlementsRepoList(pc.Auth, namespace, repository)
+	req = req.Tokens(slugPerm)
+	if start != nil {
+		req = req.Start(*start)
+	}
+	if finish != nil {
+		req = req.Finish(*finish)
+	}
+
+	metrics, _, err := pc.APIClient.MetricsApi.MetricsEntitlementsRepoListExecute(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := metrics.GetTokens()
+	return &tokens, nil
+}
+
+func dataSourceUsageByTokenRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+	repository := requiredString(d, "repository")
+	start := optionalString(d, "start")
+	finish := optionalString(d, "finish")
+	requested := expandStrings(d, "tokens")
+
+	entitlements, err := retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.RepositoryToken, *http.Response, error) {
+		req := pc.APIClient.EntitlementsApi.EntitlementsList(pc.Auth, namespace, repository)
+		req = req.Page(page)
+		req = req.PageSize(pageSize)
+		return pc.APIClient.EntitlementsApi.EntitlementsListExecute(req)
+	})
+	if err != nil {
+		return fmt.Errorf("error retrieving entitlements: %w", err)
+	}
+
+	names := make(map[string]string, len(entitlements))
+	slugPerms := []string{}
+	for _, entitlement := range entitlements {
+		names[entitlement.GetSlugPerm()] = entitlement.GetName()
+		slugPerms = append(slugPerms, entitlement.GetSlugPerm())
+	}
+
+	if len(requested) > 0 {
+		for _, slugPerm := range requested {
+			if _, ok := names[slugPerm]; !ok {
+				return fmt.Errorf("no entitlement with slug_perm '%s' found in repository %s.%s", slugPerm, namespace, repository)
+			}
+		}
+		slugPerms = requested
+	}
+
+	usage := make([]interface{}, 0, len(slugPerms))
+	for _, slugPerm := range slugPerms {
+		metrics, err := retrieveEntitlementUsage(pc, namespace, repository, slugPerm, start, finish)
+		if err != nil {
+			return fmt.Errorf("error retrieving usage for entitlement %s: %w", slugPerm, err)
+		}
+
+		bandwidth := metrics.Bandwidth.GetTotal()
+		downloads := metrics.Downloads.GetTotal()
+
+		usage = append(usage, map[string]interface{}{
+			"slug_perm":         slugPerm,
+			"name":              names[slugPerm],
+			"bandwidth":         bandwidth.GetValue(),
+			"bandwidth_units":   bandwidth.GetUnits(),
+			"bandwidth_display": bandwidth.GetDisplay(),
+			"downloads":         downloads.GetValue(),
+		})
+	}
+
+	if err := d.Set("usage", usage); err != nil {
+		return fmt.Errorf("error setting usage: %w", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", namespace, repository))
+
+	return nil
+}
+
+// dataSourceUsageByToken returns the schema and implementation for the data
+// source that reports bandwidth and download usage per entitlement token,
+// e.g. to charge consumption back to the teams each token was issued to.
+//
+//nolint:funlen
+func dataSourceUsageByToken() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceUsageByTokenRead,
+
+		Schema: map[string]*schema.Schema{
+			"finish": {
+				Type:         schema.TypeString,
+				Description:  "Include usage up to and including this UTC date or RFC 3339 timestamp.",
+				Optional:     true,
+				ValidateFunc: validateUsageDate,
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace to which the entitlements belong.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repository": {
+				Type:         schema.TypeString,
+				Description:  "Repository to which the entitlements belong.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"start": {
+				Type:         schema.TypeString,
+				Description:  "Include usage from and including this UTC date or RFC 3339 timestamp.",
+				Optional:     true,
+				ValidateFunc: validateUsageDate,
+			},
+			"tokens": {
+				Type:        schema.TypeSet,
+				Description: "The slug_perms of the entitlements to report on. Defaults to all entitlements in the repository.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+			"usage": {
+				Type:        schema.TypeList,
+				Description: "The usage of each entitlement over the requested date range.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"bandwidth": {
+							Type:        schema.TypeInt,
+							Description: "The total bandwidth used by the entitlement, expressed in bandwidth_units.",
+							Computed:    true,
+						},
+						"bandwidth_display": {
+							Type:        schema.TypeString,
+							Description: "The total bandwidth used by the entitlement, formatted for display.",
+							Computed:    true,
+						},
+						"bandwidth_units": {
+							Type:        schema.TypeString,
+							Description: "The unit in which bandwidth is expressed.",
+							Computed:    true,
+						},
+						"downloads": {
+							Type:        schema.TypeInt,
+							Description: "The total number of downloads made using the entitlement.",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "The name of the entitlement.",
+							Computed:    true,
+						},
+						"slug_perm": {
+							Type:        schema.TypeString,
+							Description: "The slug_perm of the entitlement.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
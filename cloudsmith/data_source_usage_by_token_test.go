@@ -0,0 +1,52 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccDataSourceUsageByToken creates an entitlement token and verifies its
+// (unused) usage is reported by the data source.
+func TestAccDataSourceUsageByToken(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceUsageByTokenConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cloudsmith_usage_by_token.test", "usage.#", "1"),
+					resource.TestCheckResourceAttrPair("data.cloudsmith_usage_by_token.test", "usage.0.slug_perm", "cloudsmith_entitlement.test", "id"),
+					resource.TestCheckResourceAttrPair("data.cloudsmith_usage_by_token.test", "usage.0.name", "cloudsmith_entitlement.test", "name"),
+					resource.TestCheckResourceAttr("data.cloudsmith_usage_by_token.test", "usage.0.downloads", "0"),
+				),
+			},
+		},
+	})
+}
+
+var testAccDataSourceUsageByTokenConfig = fmt.Sprintf(`
+resource "cloudsmith_repository" "test" {
+	name      = "terraform-acc-test-usage-token"
+	namespace = "%s"
+}
+
+resource "cloudsmith_entitlement" "test" {
+	name       = "Test Entitlement Usage"
+	namespace  = cloudsmith_repository.test.namespace
+	repository = cloudsmith_repository.test.slug_perm
+}
+
+data "cloudsmith_usage_by_token" "test" {
+	namespace  = cloudsmith_repository.test.namespace
+	repository = cloudsmith_repository.test.slug_perm
+	tokens     = [cloudsmith_entitlement.test.id]
+	start      = "2020-01-01"
+}
+`, os.Getenv("CLOUDSMITH_NAMESPACE"))
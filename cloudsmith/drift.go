@@ -0,0 +1,95 @@
+package cloudsmith
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// readWithDriftDetection wraps a resource's read function so that, when the
+// provider is configured with error_on_drift, any of the given attributes
+// whose value in state differs from the value returned by the API are
+// reported in an error diagnostic, failing the refresh. This allows audit
+// pipelines to detect changes made outside of Terraform instead of having
+// them silently refreshed into state.
+func readWithDriftDetection(read schema.ReadFunc, attributes ...string) schema.ReadContextFunc {
+	return func(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		pc := m.(*providerConfig)
+
+		if !pc.ErrorOnDrift || isFirstReadAfterImport(d, attributes) {
+			return diag.FromErr(read(d, m))
+		}
+
+		previous := make(map[string]interface{}, len(attributes))
+		for _, attribute := range attributes {
+			previous[attribute] = d.Get(attribute)
+		}
+
+		if err := read(d, m); err != nil {
+			return diag.FromErr(err)
+		}
+
+		// the resource no longer exists, which Terraform reports on its own
+		if d.Id() == "" {
+			return nil
+		}
+
+		drifted := []string{}
+		for _, attribute := range attributes {
+			if !driftValuesEqual(previous[attribute], d.Get(attribute)) {
+				drifted = append(drifted, attribute)
+			}
+		}
+
+		if len(drifted) == 0 {
+			return nil
+		}
+
+		return diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Resource %s was changed outside of Terraform", d.Id()),
+				Detail: fmt.Sprintf(
+					"The following attributes no longer match the values in state: %s. Revert the changes, "+
+						"or disable error_on_drift to refresh them into state.",
+					strings.Join(drifted, ", "),
+				),
+			},
+		}
+	}
+}
+
+// isFirstReadAfterImport reports whether none of the given attributes have a
+// value in the prior state, which is only the case when reading a resource
+// which has just been imported: state then holds the ID and the values set by
+// the importer, and everything read from the API would otherwise be reported
+// as drift.
+func isFirstReadAfterImport(d *schema.ResourceData, attributes []string) bool {
+	rawState := d.GetRawState()
+	if rawState.IsNull() || !rawState.IsKnown() {
+		return false
+	}
+
+	for _, attribute := range attributes {
+		if !rawState.GetAttr(attribute).IsNull() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// driftValuesEqual compares two attribute values as returned by
+// schema.ResourceData.Get, taking care to compare sets by their contents.
+func driftValuesEqual(x, y interface{}) bool {
+	if xs, ok := x.(*schema.Set); ok {
+		ys, ok := y.(*schema.Set)
+		return ok && xs.Equal(ys)
+	}
+
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,120 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestReadWithDriftDetection verifies that an error is only returned when
+// error_on_drift is enabled and the API returns values which differ from
+// those held in state.
+func TestReadWithDriftDetection(t *testing.T) {
+	t.Parallel()
+
+	resourceSchema := map[string]*schema.Schema{
+		"name": {Type: schema.TypeString, Optional: true},
+		"cidrs": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+	}
+
+	read := func(name string, cidrs ...interface{}) schema.ReadFunc {
+		return func(d *schema.ResourceData, m interface{}) error {
+			d.Set("name", name)
+			d.Set("cidrs", schema.NewSet(schema.HashString, cidrs))
+			return nil
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		errorOnDrift bool
+		read         schema.ReadFunc
+		wantError    bool
+	}{
+		{"disabled", false, read("changed", "10.0.0.0/8"), false},
+		{"unchanged", true, read("original", "192.168.0.0/16", "10.0.0.0/8"), false},
+		{"changed", true, read("original", "10.0.0.0/8"), true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{
+				"name":  "original",
+				"cidrs": []interface{}{"10.0.0.0/8", "192.168.0.0/16"},
+			})
+			d.SetId("test")
+
+			pc := &providerConfig{ErrorOnDrift: tc.errorOnDrift}
+			diags := readWithDriftDetection(tc.read, "name", "cidrs")(context.Background(), d, pc)
+
+			if gotError := len(diags) == 1 && diags[0].Severity == diag.Error; gotError != tc.wantError {
+				t.Fatalf("expected error: %t, got diagnostics: %v", tc.wantError, diags)
+			}
+		})
+	}
+}
+
+// TestReadWithDriftDetectionAfterImport verifies that the first read after an
+// import, when state holds none of the tracked attributes, isn't reported as
+// drift, while later reads still are.
+func TestReadWithDriftDetectionAfterImport(t *testing.T) {
+	t.Parallel()
+
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"organization": {Type: schema.TypeString, Optional: true},
+			"role":         {Type: schema.TypeString, Optional: true},
+		},
+	}
+
+	read := func(d *schema.ResourceData, m interface{}) error {
+		d.Set("role", "Manager")
+		return nil
+	}
+
+	testCases := []struct {
+		name       string
+		attributes map[string]string
+		role       cty.Value
+		wantError  bool
+	}{
+		{"imported", map[string]string{"id": "test", "organization": "my-org"}, cty.NullVal(cty.String), false},
+		{"refreshed", map[string]string{"id": "test", "organization": "my-org", "role": "Member"}, cty.StringVal("Member"), true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := r.Data(&terraform.InstanceState{
+				ID:         "test",
+				Attributes: tc.attributes,
+				RawState: cty.ObjectVal(map[string]cty.Value{
+					"id":           cty.StringVal("test"),
+					"organization": cty.StringVal("my-org"),
+					"role":         tc.role,
+				}),
+			})
+
+			pc := &providerConfig{ErrorOnDrift: true}
+			diags := readWithDriftDetection(read, "role")(context.Background(), d, pc)
+
+			if diags.HasError() != tc.wantError {
+				t.Fatalf("expected error: %t, got diagnostics: %v", tc.wantError, diags)
+			}
+		})
+	}
+}
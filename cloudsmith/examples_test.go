@@ -0,0 +1,190 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/samber/lo"
+)
+
+// examplesDir holds the example configurations, relative to this package.
+// Examples with a tests directory of .tftest.hcl files are applied against the
+// API by TestAccExamples, with their namespace and name variables set.
+const examplesDir = "../examples"
+
+// TestAccExamples runs `terraform test` for every example which has tests,
+// with the provider built from this tree, so that the examples users copy
+// are verified against the real API.
+func TestAccExamples(t *testing.T) {
+	t.Parallel()
+
+	if os.Getenv(resource.EnvTfAcc) == "" {
+		t.Skipf("Acceptance tests skipped unless env '%s' set", resource.EnvTfAcc)
+	}
+	testAccPreCheck(t)
+
+	terraformPath := testAccTerraformPath(t)
+	namespace := os.Getenv("CLOUDSMITH_NAMESPACE")
+	pc := testAccProviderConfig(t)
+
+	testFiles, err := filepath.Glob(filepath.Join(examplesDir, "*", "tests", "*.tftest.hcl"))
+	if err != nil {
+		t.Fatalf("unexpected error finding example tests: %s", err)
+	}
+	examples := lo.Uniq(lo.Map(testFiles, func(path string, _ int) string {
+		return filepath.Dir(filepath.Dir(path))
+	}))
+	if len(examples) == 0 {
+		t.Fatalf("expected examples with tests in %s", examplesDir)
+	}
+
+	cliConfig := testAccBuildProvider(t)
+
+	for _, example := range examples {
+		example := example
+		t.Run(filepath.Base(example), func(t *testing.T) {
+			t.Parallel()
+
+			name := testAccRandomName(filepath.Base(example))
+			testAccSweep(t, pc, namespace, name)
+
+			workDir := t.TempDir()
+			if err := copyDir(example, workDir); err != nil {
+				t.Fatalf("unexpected error copying the example: %s", err)
+			}
+
+			cmd := exec.Command(terraformPath, "test", "-var", "namespace="+namespace, "-var", "name="+name)
+			cmd.Dir = workDir
+			cmd.Env = append(os.Environ(), "TF_CLI_CONFIG_FILE="+cliConfig, "TF_IN_AUTOMATION=1")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("terraform test failed: %s\n%s", err, out)
+			}
+		})
+	}
+}
+
+// testAccTerraformPath returns the path of the Terraform CLI, from
+// TF_ACC_TERRAFORM_PATH like for the SDK's acceptance tests, or from the PATH.
+// The test is skipped if it isn't installed.
+func testAccTerraformPath(t *testing.T) string {
+	t.Helper()
+
+	if path := os.Getenv("TF_ACC_TERRAFORM_PATH"); path != "" {
+		return path
+	}
+	path, err := exec.LookPath("terraform")
+	if err != nil {
+		t.Skip("terraform must be installed to test the examples")
+	}
+	return path
+}
+
+// testAccBuildProvider builds the provider from this tree, and returns the path
+// of a Terraform CLI configuration which overrides cloudsmith-io/cloudsmith
+// with it.
+func testAccBuildProvider(t *testing.T) string {
+	t.Helper()
+
+	pluginDir := t.TempDir()
+
+	cmd := exec.Command("go", "build", "-o", filepath.Join(pluginDir, "terraform-provider-cloudsmith"), ".")
+	cmd.Dir = ".."
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("unexpected error building the provider: %s\n%s", err, out)
+	}
+
+	cliConfig := filepath.Join(pluginDir, "terraformrc")
+	contents := fmt.Sprintf(`
+		provider_installation {
+			dev_overrides {
+				"cloudsmith-io/cloudsmith" = %q
+			}
+			direct {}
+		}
+	`, pluginDir)
+	if err := os.WriteFile(cliConfig, []byte(contents), 0o600); err != nil {
+		t.Fatalf("unexpected error writing the CLI configuration: %s", err)
+	}
+
+	return cliConfig
+}
+
+// testAccSweep deletes the repositories and package deny policies of the
+// namespace whose names start with prefix when the test completes, so that
+// nothing is left behind if a test fails before destroying what it created.
+func testAccSweep(t *testing.T, pc *providerConfig, namespace, prefix string) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		repositories, err := retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.Repository, *http.Response, error) {
+			return pc.Repos.List(namespace, page, pageSize)
+		})
+		if err != nil {
+			t.Errorf("error listing repositories to clean up: %s", err)
+		}
+		for _, repository := range repositories {
+			if !strings.HasPrefix(repository.GetSlug(), prefix) {
+				continue
+			}
+			req := pc.APIClient.ReposApi.ReposDelete(pc.Auth, namespace, repository.GetSlug())
+			if _, err := pc.APIClient.ReposApi.ReposDeleteExecute(req); err != nil {
+				t.Errorf("error cleaning up repository %s: %s", repository.GetSlug(), err)
+			}
+		}
+
+		policies, err := retrieveDenyPolicies(pc, namespace)
+		if err != nil {
+			t.Errorf("error listing package deny policies to clean up: %s", err)
+		}
+		for _, policy := range policies {
+			if !strings.HasPrefix(policy.GetName(), prefix) {
+				continue
+			}
+			req := pc.APIClient.OrgsApi.OrgsDenyPolicyDelete(pc.Auth, namespace, policy.GetSlugPerm())
+			if _, err := pc.APIClient.OrgsApi.OrgsDenyPolicyDeleteExecute(req); err != nil {
+				t.Errorf("error cleaning up package deny policy %s: %s", policy.GetName(), err)
+			}
+		}
+	})
+}
+
+// copyDir copies the files of the src directory to the dst directory, except
+// for Terraform's working files, so that examples can be run in parallel
+// without sharing state.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Name() == ".terraform" || strings.HasPrefix(entry.Name(), "terraform.tfstate") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, contents, 0o600)
+	})
+}
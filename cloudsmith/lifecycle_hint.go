@@ -0,0 +1,63 @@
+package cloudsmith
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// LifecycleHint is the attribute with which resources whose objects are unique
+// server-side (e.g. a SAML group sync per idp_value and team, or a webhook
+// per target URL) choose what happens when creating one conflicts with an
+// existing object.
+const LifecycleHint = "lifecycle_hint"
+
+// Values of LifecycleHint.
+const (
+	lifecycleHintFailOnConflict  = "fail_on_conflict"
+	lifecycleHintAdoptOnConflict = "adopt_on_conflict"
+)
+
+// lifecycleHintSchema returns the schema of LifecycleHint.
+func lifecycleHintSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeString,
+		Description: "What to do when creating the object conflicts with an existing one with the same unique " +
+			"key: fail_on_conflict, or adopt_on_conflict to adopt the existing object and update it to match " +
+			"the configuration. Unless set, adopt_on_conflict is used if the provider's adopt_existing is enabled.",
+		Optional:     true,
+		Default:      lifecycleHintFailOnConflict,
+		ValidateFunc: validation.StringInSlice([]string{lifecycleHintFailOnConflict, lifecycleHintAdoptOnConflict}, false),
+	}
+}
+
+// lifecycleHint returns the LifecycleHint of a resource: its configured value
+// if it has one, otherwise adopt_on_conflict if the provider is configured to
+// adopt existing objects, or the default.
+func lifecycleHint(d *schema.ResourceData, pc *providerConfig) string {
+	raw := d.GetRawConfig()
+	configured := !raw.IsNull() && raw.IsKnown() && !raw.GetAttr(LifecycleHint).IsNull()
+	if pc.AdoptExisting && !configured {
+		return lifecycleHintAdoptOnConflict
+	}
+
+	return d.Get(LifecycleHint).(string)
+}
+
+// adoptOnConflict reports whether a failed create should be resolved by
+// adopting an existing object, i.e. the resource (or provider) asks for it and
+// the API rejected the request as invalid or conflicting rather than failing
+// to authenticate or find the parent object.
+func adoptOnConflict(d *schema.ResourceData, pc *providerConfig, resp *http.Response) bool {
+	if lifecycleHint(d, pc) != lifecycleHintAdoptOnConflict || resp == nil {
+		return false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity:
+		return true
+	default:
+		return false
+	}
+}
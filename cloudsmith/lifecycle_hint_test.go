@@ -0,0 +1,143 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAdoptOnConflict(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		lifecycleHint string
+		adoptExisting bool
+		resp          *http.Response
+		adopt         bool
+	}{
+		{"default", "", false, &http.Response{StatusCode: http.StatusConflict}, false},
+		{"conflict", lifecycleHintAdoptOnConflict, false, &http.Response{StatusCode: http.StatusConflict}, true},
+		{"bad request", lifecycleHintAdoptOnConflict, false, &http.Response{StatusCode: http.StatusBadRequest}, true},
+		{"unprocessable", lifecycleHintAdoptOnConflict, false, &http.Response{StatusCode: http.StatusUnprocessableEntity}, true},
+		{"forbidden", lifecycleHintAdoptOnConflict, false, &http.Response{StatusCode: http.StatusForbidden}, false},
+		{"no response", lifecycleHintAdoptOnConflict, false, nil, false},
+		{"adopt existing", "", true, &http.Response{StatusCode: http.StatusConflict}, true},
+		{"adopt existing forbidden", "", true, &http.Response{StatusCode: http.StatusForbidden}, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			raw := map[string]interface{}{}
+			if tc.lifecycleHint != "" {
+				raw[LifecycleHint] = tc.lifecycleHint
+			}
+			d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{LifecycleHint: lifecycleHintSchema()}, raw)
+
+			pc := &providerConfig{AdoptExisting: tc.adoptExisting}
+			if got := adoptOnConflict(d, pc, tc.resp); got != tc.adopt {
+				t.Errorf("adoptOnConflict() = %t, want %t", got, tc.adopt)
+			}
+		})
+	}
+}
+
+// TestLifecycleHintOverridesAdoptExisting verifies that a lifecycle_hint set
+// in the configuration of a resource takes precedence over the provider's
+// adopt_existing.
+func TestLifecycleHintOverridesAdoptExisting(t *testing.T) {
+	t.Parallel()
+
+	r := &schema.Resource{Schema: map[string]*schema.Schema{
+		"name":        {Type: schema.TypeString, Optional: true},
+		LifecycleHint: lifecycleHintSchema(),
+	}}
+	pc := &providerConfig{AdoptExisting: true}
+
+	testCases := []struct {
+		name          string
+		lifecycleHint cty.Value
+		want          string
+	}{
+		{"unset", cty.NullVal(cty.String), lifecycleHintAdoptOnConflict},
+		{"fail on conflict", cty.StringVal(lifecycleHintFailOnConflict), lifecycleHintFailOnConflict},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := map[string]interface{}{"name": "test"}
+			if !tc.lifecycleHint.IsNull() {
+				config[LifecycleHint] = tc.lifecycleHint.AsString()
+			}
+			diff, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), pc)
+			if err != nil {
+				t.Fatalf("unexpected error planning: %s", err)
+			}
+
+			// Terraform sends the configuration along with the plan
+			diff.RawConfig = cty.ObjectVal(map[string]cty.Value{
+				"name":        cty.StringVal("test"),
+				LifecycleHint: tc.lifecycleHint,
+			})
+			d, err := schema.InternalMap(r.Schema).Data(nil, diff)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got := lifecycleHint(d, pc); got != tc.want {
+				t.Errorf("lifecycleHint() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAdoptGroupSync(t *testing.T) {
+	t.Parallel()
+
+	saml := &fakeSAMLService{syncs: []cloudsmith.OrganizationGroupSync{{
+		IdpKey:   "groups",
+		IdpValue: "developers",
+		Role:     cloudsmith.PtrString("Member"),
+		SlugPerm: cloudsmith.PtrString("AbCdEfGhIjKl"),
+		Team:     "dev",
+	}}}
+	pc := &providerConfig{SAML: saml}
+
+	data := cloudsmith.OrganizationGroupSyncRequest{
+		IdpKey:       "groups",
+		IdpValue:     "developers",
+		Organization: "my-org",
+		Role:         cloudsmith.PtrString("Member"),
+		Team:         "dev",
+	}
+	_, _, createErr := saml.CreateGroupSync("my-org", data)
+	if createErr == nil {
+		t.Fatal("expected creating a duplicate group sync to fail")
+	}
+
+	adopted, err := adoptGroupSync(pc, "my-org", data, createErr)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if adopted.GetSlugPerm() != "AbCdEfGhIjKl" {
+		t.Errorf("expected the existing group sync to be adopted, got %q", adopted.GetSlugPerm())
+	}
+
+	data.Team = "ops"
+	if _, err := adoptGroupSync(pc, "my-org", data, createErr); !errors.Is(err, createErr) {
+		t.Errorf("expected the create error without a matching group sync, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package cloudsmith
+
+import (
+	"strings"
+	"sync"
+)
+
+// mutexKV is a set of mutexes identified by name, created as they are first
+// used, which lets operations on the same object be serialized across the
+// resources Terraform applies in parallel.
+type mutexKV struct {
+	mu    sync.Mutex
+	store map[string]*sync.Mutex
+}
+
+func newMutexKV() *mutexKV {
+	return &mutexKV{store: map[string]*sync.Mutex{}}
+}
+
+// get returns the mutex with the given name, creating it if needed.
+func (m *mutexKV) get(name string) *sync.Mutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mutex, ok := m.store[name]
+	if !ok {
+		mutex = &sync.Mutex{}
+		m.store[name] = mutex
+	}
+	return mutex
+}
+
+// lock locks the mutex with the given name, and returns a function to unlock
+// it, so that it can be held for the rest of a function with
+// `defer m.lock(name)()`.
+func (m *mutexKV) lock(name string) func() {
+	mutex := m.get(name)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+// organizationLocks serializes writes to organization-level settings, such as
+// SAML group syncs, which the API can reject with a 409 Conflict if they are
+// modified concurrently. Locks are named after the organization's slug_perm,
+// so that the same lock is taken however the organization is referred to;
+// names are case-insensitive, like organization slugs.
+var organizationLocks = newMutexKV()
+
+// lockOrganization locks writes to the settings of an organization, identified
+// by its slug_perm, and returns a function to unlock them.
+func lockOrganization(organization string) func() {
+	return organizationLocks.lock(strings.ToLower(organization))
+}
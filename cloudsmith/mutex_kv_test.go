@@ -0,0 +1,101 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMutexKV(t *testing.T) {
+	t.Parallel()
+
+	m := newMutexKV()
+
+	// holders of the same lock never overlap
+	var holders, maxHolders int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer m.lock("my-org")()
+
+			n := atomic.AddInt32(&holders, 1)
+			for {
+				max := atomic.LoadInt32(&maxHolders)
+				if n <= max || atomic.CompareAndSwapInt32(&maxHolders, max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&holders, -1)
+		}()
+	}
+	wg.Wait()
+
+	if maxHolders != 1 {
+		t.Errorf("expected the lock to be held by one goroutine at a time, got %d", maxHolders)
+	}
+
+	// locks with different names are independent
+	unlock := m.lock("my-org")
+	done := make(chan struct{})
+	go func() {
+		defer m.lock("other-org")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("expected locking another name not to wait")
+	}
+	unlock()
+}
+
+func TestLockOrganization(t *testing.T) {
+	t.Parallel()
+
+	unlock := lockOrganization("Lock-Test-Org")
+	if organizationLocks.get("lock-test-org").TryLock() {
+		t.Error("expected organization locks to be case-insensitive")
+	}
+	unlock()
+}
+
+// TestSAMLServiceLockOrganization verifies that writes to the group syncs of an
+// organization take the same lock whether it is referred to by its slug or by
+// its slug_perm.
+func TestSAMLServiceLockOrganization(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/lock-test-saml-org/", "/orgs/LoCkTeStSlUgPeRm/":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"name": "Lock Test", "slug": "lock-test-saml-org", "slug_perm": "LoCkTeStSlUgPeRm"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	pc, diags := newProviderConfig(context.Background(), server.URL, "api-key", "test", httpClientOptions{})
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	saml := pc.SAML.(*apiSAMLService)
+
+	for _, organization := range []string{"lock-test-saml-org", "LoCkTeStSlUgPeRm"} {
+		unlock := saml.lockOrganization(organization)
+		if organizationLocks.get("locktestslugperm").TryLock() {
+			t.Errorf("expected referring to the organization as %s to lock its slug_perm", organization)
+		}
+		unlock()
+	}
+}
@@ -0,0 +1,97 @@
+package cloudsmith
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// namespaceAliases maps each of the names used for the namespace (organization)
+// a resource belongs to onto the other. Resources use one or the other for
+// historical reasons, and both are accepted everywhere.
+var namespaceAliases = map[string]string{
+	"namespace":    "organization",
+	"organization": "namespace",
+}
+
+// aliasNamespaceAttribute adds a deprecated alias for the required namespace
+// or organization attribute of a resource or data source, so that either name
+// can be used. The original attribute becomes computed from the alias when
+// only the alias is configured, so the rest of the implementation needn't know
+// which was used.
+func aliasNamespaceAttribute(r *schema.Resource) {
+	for name, alias := range namespaceAliases {
+		s, ok := r.Schema[name]
+		if !ok || !s.Required || r.Schema[alias] != nil {
+			continue
+		}
+
+		// changing which name is used shouldn't replace the resource, which
+		// requires an update function even if there's nothing to update
+		writable := r.Create != nil || r.CreateContext != nil || r.CreateWithoutTimeout != nil
+		hasUpdate := r.Update != nil || r.UpdateContext != nil || r.UpdateWithoutTimeout != nil
+
+		s.Required = false
+		s.Optional = true
+		s.Computed = true
+		s.ExactlyOneOf = []string{name, alias}
+
+		r.Schema[alias] = &schema.Schema{
+			Type:             schema.TypeString,
+			Description:      fmt.Sprintf("Alias of %s.", name),
+			Optional:         true,
+			ForceNew:         writable && !hasUpdate && s.ForceNew,
+			Deprecated:       fmt.Sprintf("%s is an alias of %s, use %s instead.", alias, name, name),
+			ConflictsWith:    []string{name},
+			ValidateFunc:     s.ValidateFunc,
+			ValidateDiagFunc: s.ValidateDiagFunc,
+		}
+
+		if writable {
+			customizeDiffs := []schema.CustomizeDiffFunc{customizeDiffNamespaceAlias(name, alias)}
+			if r.CustomizeDiff != nil {
+				customizeDiffs = append(customizeDiffs, r.CustomizeDiff)
+			}
+			r.CustomizeDiff = customdiff.All(customizeDiffs...)
+		}
+
+		read := r.ReadContext
+		if read == nil {
+			legacyRead := r.Read
+			read = func(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+				return diag.FromErr(legacyRead(d, m))
+			}
+			r.Read = nil
+		}
+
+		// data sources have no plan, so the original attribute is set from the
+		// alias before reading
+		r.ReadContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			if value, ok := d.GetOk(alias); ok {
+				d.Set(name, value)
+			}
+			return read(ctx, d, m)
+		}
+
+		return
+	}
+}
+
+// customizeDiffNamespaceAlias plans the value of the original namespace or
+// organization attribute from its alias, when the alias is configured.
+func customizeDiffNamespaceAlias(name, alias string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if !d.NewValueKnown(alias) {
+			return d.SetNewComputed(name)
+		}
+
+		if value, ok := d.GetOk(alias); ok && value != d.Get(name) {
+			return d.SetNew(name, value)
+		}
+
+		return nil
+	}
+}
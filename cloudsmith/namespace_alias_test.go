@@ -0,0 +1,97 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestAliasNamespaceAttributeResource verifies that configuring the alias of
+// the organization attribute of a resource plans the organization itself.
+func TestAliasNamespaceAttributeResource(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		config map[string]interface{}
+	}{
+		{"original", map[string]interface{}{"organization": "my-org", "name": "team"}},
+		{"alias", map[string]interface{}{"namespace": "my-org", "name": "team"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := resourceTeam()
+			aliasNamespaceAttribute(r)
+
+			if r.Schema["namespace"] == nil || r.Schema["namespace"].Deprecated == "" {
+				t.Fatal("expected a deprecated namespace alias")
+			}
+
+			config := terraform.NewResourceConfigRaw(tc.config)
+			if diags := r.Validate(config); diags.HasError() {
+				t.Fatalf("unexpected validation error: %v", diags)
+			}
+
+			diff, err := r.Diff(context.Background(), nil, config, &providerConfig{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got := diff.Attributes["organization"]; got == nil || got.New != "my-org" {
+				t.Fatalf("expected organization to be planned as my-org, got: %v", got)
+			}
+		})
+	}
+}
+
+// TestAliasNamespaceAttributeConflict verifies that exactly one of the
+// attribute and its alias must be configured.
+func TestAliasNamespaceAttributeConflict(t *testing.T) {
+	t.Parallel()
+
+	r := resourceTeam()
+	aliasNamespaceAttribute(r)
+
+	for _, config := range []map[string]interface{}{
+		{"organization": "my-org", "namespace": "my-org", "name": "team"},
+		{"name": "team"},
+	} {
+		if diags := r.Validate(terraform.NewResourceConfigRaw(config)); !diags.HasError() {
+			t.Errorf("expected validation error for config %v", config)
+		}
+	}
+}
+
+// TestAliasNamespaceAttributeDataSource verifies that the alias of the
+// namespace attribute of a data source is copied before reading.
+func TestAliasNamespaceAttributeDataSource(t *testing.T) {
+	t.Parallel()
+
+	var namespace string
+	r := &schema.Resource{
+		Read: func(d *schema.ResourceData, m interface{}) error {
+			namespace = requiredString(d, "namespace")
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"namespace": {Type: schema.TypeString, Required: true},
+		},
+	}
+	aliasNamespaceAttribute(r)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"organization": "my-org"})
+	if diags := r.ReadContext(context.Background(), d, &providerConfig{}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if namespace != "my-org" {
+		t.Fatalf("expected namespace my-org, got %q", namespace)
+	}
+}
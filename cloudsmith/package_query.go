@@ -0,0 +1,539 @@
+package cloudsmith
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/samber/lo"
+)
+
+// The package search syntax used by package queries is Lucene-like: terms are
+// either free text or field:value pairs, optionally negated with a leading ~,
+// combined with the (case-insensitive) AND, OR and NOT operators, where
+// adjacent terms are implicitly combined with AND, and grouped with
+// parentheses. Values may be quoted and, for numeric, date and version
+// fields, prefixed with a comparison operator, e.g. `format:python AND (name:^django OR tag:"release candidate") NOT size:>1000`.
+
+// packageQueryFieldPattern matches the name of a field in a field:value term.
+var packageQueryFieldPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// versionPartPattern matches the runs of digits and letters compared when
+// evaluating version comparisons.
+var versionPartPattern = regexp.MustCompile(`[0-9]+|[a-z]+`)
+
+// packageQueryComparisons are the operators which may prefix a value, longest
+// first so that e.g. ">=" isn't mistaken for ">".
+var packageQueryComparisons = []string{">=", "<=", ">", "<", "="}
+
+// packageQueryError is a syntax error in a package query. Position is the
+// 1-based column of the character at which the error was detected.
+type packageQueryError struct {
+	Position int
+	Message  string
+}
+
+func (e *packageQueryError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+// packageQueryNode is a node of a parsed package query.
+type packageQueryNode interface {
+	isPackageQueryNode()
+}
+
+// packageQueryTerm matches packages whose Field (or any field, for free text
+// terms) matches Value, compared using Comparison if one was given.
+type packageQueryTerm struct {
+	Field      string
+	Comparison string
+	Value      string
+	Position   int
+}
+
+type packageQueryAnd struct {
+	Left, Right packageQueryNode
+}
+
+type packageQueryOr struct {
+	Left, Right packageQueryNode
+}
+
+type packageQueryNot struct {
+	Operand packageQueryNode
+}
+
+func (packageQueryTerm) isPackageQueryNode() {}
+func (packageQueryAnd) isPackageQueryNode()  {}
+func (packageQueryOr) isPackageQueryNode()   {}
+func (packageQueryNot) isPackageQueryNode()  {}
+
+type packageQueryTokenKind int
+
+const (
+	packageQueryTokenWord packageQueryTokenKind = iota
+	packageQueryTokenAnd
+	packageQueryTokenOr
+	packageQueryTokenNot
+	packageQueryTokenOpen
+	packageQueryTokenClose
+	packageQueryTokenEnd
+)
+
+type packageQueryToken struct {
+	Kind     packageQueryTokenKind
+	Text     string
+	Position int
+}
+
+// tokenizePackageQuery splits a package query into words, operators and
+// parentheses. Quoted sections of words may contain whitespace and
+// parentheses, and the quotes are kept in the word text.
+func tokenizePackageQuery(query string) ([]packageQueryToken, error) {
+	runes := []rune(query)
+	tokens := []packageQueryToken{}
+
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, packageQueryToken{Kind: packageQueryTokenOpen, Text: "(", Position: i + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, packageQueryToken{Kind: packageQueryTokenClose, Text: ")", Position: i + 1})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				if quote := runes[i]; quote == '"' || quote == '\'' {
+					end := i + 1
+					for end < len(runes) && runes[end] != quote {
+						end++
+					}
+					if end == len(runes) {
+						return nil, &packageQueryError{Position: i + 1, Message: "unterminated quoted string"}
+					}
+					i = end
+				}
+				i++
+			}
+
+			word := string(runes[start:i])
+			kind := packageQueryTokenWord
+			switch strings.ToUpper(word) {
+			case "AND":
+				kind = packageQueryTokenAnd
+			case "OR":
+				kind = packageQueryTokenOr
+			case "NOT":
+				kind = packageQueryTokenNot
+			}
+			tokens = append(tokens, packageQueryToken{Kind: kind, Text: word, Position: start + 1})
+		}
+	}
+
+	return append(tokens, packageQueryToken{Kind: packageQueryTokenEnd, Position: len(runes) + 1}), nil
+}
+
+// packageQueryParser is a recursive descent parser for package queries. The
+// grammar, from lowest to highest precedence, is:
+//
+//	query   = or
+//	or      = and { "OR" and }
+//	and     = unary { [ "AND" ] unary }
+//	unary   = "NOT" unary | primary
+//	primary = "(" or ")" | term
+type packageQueryParser struct {
+	tokens []packageQueryToken
+	pos    int
+}
+
+func (p *packageQueryParser) peek() packageQueryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *packageQueryParser) next() packageQueryToken {
+	token := p.tokens[p.pos]
+	if token.Kind != packageQueryTokenEnd {
+		p.pos++
+	}
+	return token
+}
+
+func (p *packageQueryParser) parseOr() (packageQueryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().Kind == packageQueryTokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = packageQueryOr{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *packageQueryParser) parseAnd() (packageQueryNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek().Kind {
+		case packageQueryTokenAnd:
+			p.next()
+		case packageQueryTokenWord, packageQueryTokenNot, packageQueryTokenOpen:
+			// adjacent terms are implicitly combined with AND
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = packageQueryAnd{Left: left, Right: right}
+	}
+}
+
+func (p *packageQueryParser) parseUnary() (packageQueryNode, error) {
+	if p.peek().Kind == packageQueryTokenNot {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return packageQueryNot{Operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *packageQueryParser) parsePrimary() (packageQueryNode, error) {
+	token := p.next()
+
+	switch token.Kind {
+	case packageQueryTokenOpen:
+		if p.peek().Kind == packageQueryTokenClose {
+			return nil, &packageQueryError{Position: token.Position, Message: "empty parentheses"}
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().Kind != packageQueryTokenClose {
+			return nil, &packageQueryError{Position: token.Position, Message: "unclosed parenthesis"}
+		}
+		p.next()
+		return node, nil
+	case packageQueryTokenWord:
+		return parsePackageQueryTerm(token)
+	case packageQueryTokenEnd:
+		return nil, &packageQueryError{Position: token.Position, Message: "unexpected end of query, expected a search term"}
+	default:
+		return nil, &packageQueryError{Position: token.Position, Message: fmt.Sprintf("unexpected %q, expected a search term", token.Text)}
+	}
+}
+
+// parsePackageQueryTerm parses a single word of a query into a term, which is
+// wrapped in a NOT if it is negated with a leading ~.
+func parsePackageQueryTerm(token packageQueryToken) (packageQueryNode, error) {
+	text := token.Text
+	position := token.Position
+
+	negated := strings.HasPrefix(text, "~")
+	if negated {
+		text = text[1:]
+		position++
+		if text == "" {
+			return nil, &packageQueryError{Position: token.Position, Message: "expected a search term after \"~\""}
+		}
+	}
+
+	term := packageQueryTerm{Value: text, Position: position}
+
+	// a colon inside quotes is part of a free text value, not a field
+	if colon := strings.Index(text, ":"); colon >= 0 && !strings.ContainsAny(text[:colon], `"'`) {
+		term.Field = text[:colon]
+		term.Value = text[colon+1:]
+
+		if !packageQueryFieldPattern.MatchString(term.Field) {
+			return nil, &packageQueryError{Position: position, Message: fmt.Sprintf("invalid field name %q", term.Field)}
+		}
+
+		for _, comparison := range packageQueryComparisons {
+			if strings.HasPrefix(term.Value, comparison) {
+				term.Comparison = comparison
+				term.Value = term.Value[len(comparison):]
+				break
+			}
+		}
+
+		if term.Value == "" {
+			return nil, &packageQueryError{
+				Position: position + len([]rune(text)),
+				Message:  fmt.Sprintf("missing value for field %q", term.Field),
+			}
+		}
+	}
+
+	term.Value = unquotePackageQueryValue(term.Value)
+
+	if negated {
+		return packageQueryNot{Operand: term}, nil
+	}
+	return term, nil
+}
+
+// unquotePackageQueryValue removes the quotes around a fully quoted value.
+func unquotePackageQueryValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// parsePackageQuery parses a package query, returning a *packageQueryError
+// describing the first syntax error found, if any.
+func parsePackageQuery(query string) (packageQueryNode, error) {
+	tokens, err := tokenizePackageQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &packageQueryParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if token := p.peek(); token.Kind != packageQueryTokenEnd {
+		return nil, &packageQueryError{Position: token.Position, Message: fmt.Sprintf("unexpected %q", token.Text)}
+	}
+
+	return node, nil
+}
+
+// validatePackageQuery is a ValidateDiagFunc for optional package query
+// attributes, which reports syntax errors at plan time rather than leaving the
+// API to silently match no packages. An empty query means no query, as it
+// always has for these attributes.
+func validatePackageQuery(i interface{}, path cty.Path) diag.Diagnostics {
+	if query, ok := i.(string); ok && query == "" {
+		return nil
+	}
+
+	return validateRequiredPackageQuery(i, path)
+}
+
+// validateRequiredPackageQuery is validatePackageQuery for required package
+// query attributes, for which an empty query is an error.
+func validateRequiredPackageQuery(i interface{}, path cty.Path) diag.Diagnostics {
+	query, ok := i.(string)
+	if !ok {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid package query",
+			Detail:        fmt.Sprintf("expected type of package query to be string, got %T", i),
+			AttributePath: path,
+		}}
+	}
+
+	if _, err := parsePackageQuery(query); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid package query",
+			Detail:        fmt.Sprintf("%q: %s", query, err),
+			AttributePath: path,
+		}}
+	}
+
+	return nil
+}
+
+// packageQueryPackage describes the package a query is evaluated against.
+// Format and Version may be empty if unknown, and Tags nil.
+type packageQueryPackage struct {
+	Format  string
+	Name    string
+	Tags    []string
+	Version string
+}
+
+// evaluatePackageQuery approximates whether a package query matches the given
+// package. Only the name, version, format and tag fields can be evaluated, so
+// the second value is false if the result depends on anything else (e.g.
+// downloads or size), or on a field whose value is unknown.
+func evaluatePackageQuery(node packageQueryNode, pkg packageQueryPackage) (bool, bool) {
+	switch n := node.(type) {
+	case packageQueryAnd:
+		// a definite non-match on either side decides the result
+		left, leftKnown := evaluatePackageQuery(n.Left, pkg)
+		if leftKnown && !left {
+			return false, true
+		}
+		right, rightKnown := evaluatePackageQuery(n.Right, pkg)
+		if rightKnown && !right {
+			return false, true
+		}
+		return true, leftKnown && rightKnown
+	case packageQueryOr:
+		// a definite match on either side decides the result
+		left, leftKnown := evaluatePackageQuery(n.Left, pkg)
+		if leftKnown && left {
+			return true, true
+		}
+		right, rightKnown := evaluatePackageQuery(n.Right, pkg)
+		if rightKnown && right {
+			return true, true
+		}
+		return false, leftKnown && rightKnown
+	case packageQueryNot:
+		matched, known := evaluatePackageQuery(n.Operand, pkg)
+		return !matched, known
+	case packageQueryTerm:
+		return evaluatePackageQueryTerm(n, pkg)
+	default:
+		return false, false
+	}
+}
+
+func evaluatePackageQueryTerm(term packageQueryTerm, pkg packageQueryPackage) (bool, bool) {
+	textual := term.Comparison == "" || term.Comparison == "="
+
+	switch term.Field {
+	case "":
+		// free text also searches fields other than the name (e.g. the
+		// description), so only a match is definite
+		if matchPackageQueryValue(pkg.Name, term.Value) {
+			return true, true
+		}
+		return false, false
+	case "name":
+		return matchPackageQueryValue(pkg.Name, term.Value), textual
+	case "format":
+		return strings.EqualFold(pkg.Format, term.Value), textual && pkg.Format != ""
+	case "tag":
+		return lo.SomeBy(pkg.Tags, func(tag string) bool {
+			return matchPackageQueryValue(tag, term.Value)
+		}), textual
+	case "version":
+		if pkg.Version == "" {
+			return false, false
+		}
+		if textual {
+			return matchPackageQueryValue(pkg.Version, term.Value), true
+		}
+		cmp := compareVersions(pkg.Version, term.Value)
+		switch term.Comparison {
+		case ">":
+			return cmp > 0, true
+		case ">=":
+			return cmp >= 0, true
+		case "<":
+			return cmp < 0, true
+		default:
+			return cmp <= 0, true
+		}
+	default:
+		return false, false
+	}
+}
+
+// matchPackageQueryValue reports whether a field value matches a query value,
+// case-insensitively. Values match anywhere in the field unless anchored to
+// its start with ^ or its end with $.
+func matchPackageQueryValue(field, value string) bool {
+	field = strings.ToLower(field)
+	value = strings.ToLower(value)
+
+	start := strings.HasPrefix(value, "^")
+	value = strings.TrimPrefix(value, "^")
+	end := strings.HasSuffix(value, "$")
+	value = strings.TrimSuffix(value, "$")
+
+	switch {
+	case start && end:
+		return field == value
+	case start:
+		return strings.HasPrefix(field, value)
+	case end:
+		return strings.HasSuffix(field, value)
+	default:
+		return strings.Contains(field, value)
+	}
+}
+
+// compareVersions compares two versions by their runs of digits and letters,
+// numerically for digits, returning -1, 0 or 1. Letters sort before digits,
+// so that pre-releases sort before releases, e.g. 1.0rc1 < 1.0 < 1.0.1.
+func compareVersions(a, b string) int {
+	aParts := versionParts(strings.ToLower(a))
+	bParts := versionParts(strings.ToLower(b))
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		// a missing part sorts after letters but before digits
+		if i >= len(aParts) {
+			return -compareMissingVersionPart(bParts[i])
+		}
+		if i >= len(bParts) {
+			return compareMissingVersionPart(aParts[i])
+		}
+		if cmp := compareVersionParts(aParts[i], bParts[i]); cmp != 0 {
+			return cmp
+		}
+	}
+
+	return 0
+}
+
+// versionParts splits a version into runs of digits and runs of letters,
+// dropping separators.
+func versionParts(version string) []string {
+	return versionPartPattern.FindAllString(version, -1)
+}
+
+func isNumericVersionPart(part string) bool {
+	return part[0] >= '0' && part[0] <= '9'
+}
+
+func compareMissingVersionPart(part string) int {
+	if isNumericVersionPart(part) {
+		return 1
+	}
+	return -1
+}
+
+func compareVersionParts(a, b string) int {
+	aNumeric := isNumericVersionPart(a)
+	bNumeric := isNumericVersionPart(b)
+
+	switch {
+	case aNumeric && bNumeric:
+		a = strings.TrimLeft(a, "0")
+		b = strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	case aNumeric:
+		return 1
+	case bNumeric:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
@@ -0,0 +1,226 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParsePackageQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		query    string
+		expected packageQueryNode
+	}{
+		{
+			"format:python",
+			packageQueryTerm{Field: "format", Value: "python", Position: 1},
+		},
+		{
+			"django",
+			packageQueryTerm{Value: "django", Position: 1},
+		},
+		{
+			"format:python AND downloads:>50",
+			packageQueryAnd{
+				Left:  packageQueryTerm{Field: "format", Value: "python", Position: 1},
+				Right: packageQueryTerm{Field: "downloads", Comparison: ">", Value: "50", Position: 19},
+			},
+		},
+		{
+			"format:docker and repository:shared-proxy-test",
+			packageQueryAnd{
+				Left:  packageQueryTerm{Field: "format", Value: "docker", Position: 1},
+				Right: packageQueryTerm{Field: "repository", Value: "shared-proxy-test", Position: 19},
+			},
+		},
+		{
+			"name:a OR name:b name:c",
+			packageQueryOr{
+				Left: packageQueryTerm{Field: "name", Value: "a", Position: 1},
+				Right: packageQueryAnd{
+					Left:  packageQueryTerm{Field: "name", Value: "b", Position: 11},
+					Right: packageQueryTerm{Field: "name", Value: "c", Position: 18},
+				},
+			},
+		},
+		{
+			`(name:a OR tag:"release candidate") NOT ~version:<=1.0`,
+			packageQueryAnd{
+				Left: packageQueryOr{
+					Left:  packageQueryTerm{Field: "name", Value: "a", Position: 2},
+					Right: packageQueryTerm{Field: "tag", Value: "release candidate", Position: 12},
+				},
+				Right: packageQueryNot{
+					Operand: packageQueryNot{
+						Operand: packageQueryTerm{Field: "version", Comparison: "<=", Value: "1.0", Position: 42},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := parsePackageQuery(tc.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("expected %#v, got %#v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParsePackageQueryErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		query    string
+		position int
+	}{
+		{"", 1},
+		{"format:python AND", 18},
+		{"OR name:a", 1},
+		{"name:a AND OR name:b", 12},
+		{"(name:a OR name:b", 1},
+		{"name:a)", 7},
+		{"()", 1},
+		{`tag:"unterminated`, 5},
+		{"name:", 6},
+		{"downloads:>", 12},
+		{"Name:a", 1},
+		{"~", 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parsePackageQuery(tc.query)
+
+			var queryErr *packageQueryError
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("expected a package query error, got: %v", err)
+			}
+			if queryErr.Position != tc.position {
+				t.Fatalf("expected error at position %d, got: %s", tc.position, queryErr)
+			}
+		})
+	}
+}
+
+// TestValidatePackageQuery verifies that an empty query is only rejected for
+// required package query attributes.
+func TestValidatePackageQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		validate schema.SchemaValidateDiagFunc
+		query    string
+		wantErr  bool
+	}{
+		{"optional empty", validatePackageQuery, "", false},
+		{"optional valid", validatePackageQuery, "format:docker", false},
+		{"optional invalid", validatePackageQuery, "format:docker AND", true},
+		{"required empty", validateRequiredPackageQuery, "", true},
+		{"required valid", validateRequiredPackageQuery, "format:docker", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diags := tc.validate(tc.query, cty.Path{}); diags.HasError() != tc.wantErr {
+				t.Fatalf("expected error: %t, got diagnostics: %v", tc.wantErr, diags)
+			}
+		})
+	}
+}
+
+func TestEvaluatePackageQuery(t *testing.T) {
+	t.Parallel()
+
+	pkg := packageQueryPackage{Format: "python", Name: "Django", Tags: []string{"latest"}, Version: "4.2.1"}
+
+	testCases := []struct {
+		query   string
+		matched bool
+		known   bool
+	}{
+		{"name:django", true, true},
+		{"name:^djan", true, true},
+		{"name:^jango", false, true},
+		{"name:^django$", true, true},
+		{"format:python AND name:flask", false, true},
+		{"format:npm OR name:django", true, true},
+		{"NOT format:python", false, true},
+		{"~tag:latest", false, true},
+		{"tag:beta", false, true},
+		{"version:>=4.2", true, true},
+		{"version:<4.2.0", false, true},
+		{"version:>4.2rc1", true, true},
+		{"django", true, true},
+		{"flask", false, false},
+		{"downloads:>50", false, false},
+		{"downloads:>50 AND format:npm", false, true},
+		{"downloads:>50 OR format:python", true, true},
+		{"downloads:>50 OR format:npm", false, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			node, err := parsePackageQuery(tc.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			matched, known := evaluatePackageQuery(node, pkg)
+			if known != tc.known || (known && matched != tc.matched) {
+				t.Errorf("evaluatePackageQuery() = (%t, %t), want (%t, %t)", matched, known, tc.matched, tc.known)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.1", -1},
+		{"1.10", "1.9", 1},
+		{"1.0rc1", "1.0", -1},
+		{"1.0-alpha", "1.0-beta", -1},
+		{"v2", "V2", 0},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			t.Parallel()
+
+			if got := compareVersions(tc.a, tc.b); got != tc.expected {
+				t.Errorf("compareVersions(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.expected)
+			}
+		})
+	}
+}
@@ -5,11 +5,17 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
 )
 
+// anonymousUserAgent is the user agent sent when telemetry is disabled.
+const anonymousUserAgent = "terraform-provider-cloudsmith"
+
 // Provider returns a terraform.ResourceProvider.
 func Provider() *schema.Provider {
 	p := &schema.Provider{
@@ -27,40 +33,139 @@ func Provider() *schema.Provider {
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("CLOUDSMITH_API_HOST", "https://api.cloudsmith.io/v1"),
 			},
+			"error_on_drift": {
+				Type: schema.TypeBool,
+				Description: "If enabled, reading a resource fails with an error listing any attributes that " +
+					"were changed outside of Terraform, rather than silently refreshing them into state.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSMITH_ERROR_ON_DRIFT", false),
+			},
+			"redact_sensitive_outputs": {
+				Type: schema.TypeBool,
+				Description: "If enabled, sensitive values such as tokens are left empty in data sources " +
+					"unless explicitly requested, to avoid spreading secrets through state.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSMITH_REDACT_SENSITIVE_OUTPUTS", false),
+			},
+			"error_on_suspended_namespace": {
+				Type: schema.TypeBool,
+				Description: "If enabled, refreshing a resource in a suspended namespace fails with an error. " +
+					"Otherwise a warning is returned and the resource is left in state as it was.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSMITH_ERROR_ON_SUSPENDED_NAMESPACE", false),
+			},
+			"adopt_existing": {
+				Type: schema.TypeBool,
+				Description: "If enabled, resources whose objects are unique server-side (webhooks by target URL " +
+					"and SAML group syncs by IdP key, value and team) adopt an existing object when creating one " +
+					"conflicts with it, unless their lifecycle_hint is set. Useful to bring hand-managed " +
+					"organizations under management.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSMITH_ADOPT_EXISTING", false),
+			},
+			"disable_telemetry": {
+				Type: schema.TypeBool,
+				Description: "If enabled, the user agent of API requests only identifies the provider, without " +
+					"the operating system, architecture or Terraform version it is running on.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSMITH_DISABLE_TELEMETRY", false),
+			},
+			"additional_retryable_status_codes": {
+				Type: schema.TypeSet,
+				Description: "HTTP status codes which, in addition to 429, 502, 503 and 504, cause API requests " +
+					"to be retried, e.g. nonstandard codes returned by gateways in front of Cloudsmith.",
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntBetween(100, 599),
+				},
+			},
+			"http_timeout": {
+				Type: schema.TypeInt,
+				Description: "The time limit in seconds for each API request, including any retries. " +
+					"Zero (the default) means no limit.",
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CLOUDSMITH_HTTP_TIMEOUT", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_idle_conns": {
+				Type: schema.TypeInt,
+				Description: "The maximum number of idle (keep-alive) connections to the API kept open for reuse. " +
+					"Zero (the default) means 100.",
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CLOUDSMITH_MAX_IDLE_CONNS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_conns_per_host": {
+				Type:         schema.TypeInt,
+				Description:  "The maximum number of concurrent connections to the API. Zero (the default) means no limit.",
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CLOUDSMITH_MAX_CONNS_PER_HOST", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"cloudsmith_namespace":             dataSourceNamespace(),
-			"cloudsmith_organization":          dataSourceOrganization(),
-			"cloudsmith_package":               dataSourcePackage(),
-			"cloudsmith_package_list":          dataSourcePackageList(),
-			"cloudsmith_repository":            dataSourceRepository(),
-			"cloudsmith_repository_privileges": dataSourceRepositoryPrivileges(),
-			"cloudsmith_package_deny_policy":   dataSourcePackageDenyPolicy(),
-			"cloudsmith_entitlement_list":      dataSourceEntitlementList(),
-			"cloudsmith_list_org_members":      dataSourceOrganizationMembersList(),
-			"cloudsmith_org_member_details":    dataSourceMemberDetails(),
-			"cloudsmith_user_self":             dataSourceUserSelf(),
+			"cloudsmith_entitlement":                   dataSourceEntitlement(),
+			"cloudsmith_formats":                       dataSourceFormats(),
+			"cloudsmith_namespace":                     dataSourceNamespace(),
+			"cloudsmith_organization":                  dataSourceOrganization(),
+			"cloudsmith_package":                       dataSourcePackage(),
+			"cloudsmith_package_list":                  dataSourcePackageList(),
+			"cloudsmith_package_file_parts":            dataSourcePackageFileParts(),
+			"cloudsmith_policies":                      dataSourcePolicies(),
+			"cloudsmith_rate_limits":                   dataSourceRateLimits(),
+			"cloudsmith_repository":                    dataSourceRepository(),
+			"cloudsmith_repository_privileges":         dataSourceRepositoryPrivileges(),
+			"cloudsmith_repositories_privilege_report": dataSourceRepositoriesPrivilegeReport(),
+			"cloudsmith_saml_group_sync_preview":       dataSourceSAMLGroupSyncPreview(),
+			"cloudsmith_security_posture":              dataSourceSecurityPosture(),
+			"cloudsmith_unprotected_repositories":      dataSourceUnprotectedRepositories(),
+			"cloudsmith_package_deny_policy":           dataSourcePackageDenyPolicy(),
+			"cloudsmith_package_deny_policy_preview":   dataSourcePackageDenyPolicyPreview(),
+			"cloudsmith_entitlement_list":              dataSourceEntitlementList(),
+			"cloudsmith_entitlement_eula_acceptances":  dataSourceEntitlementEulaAcceptances(),
+			"cloudsmith_list_org_members":              dataSourceOrganizationMembersList(),
+			"cloudsmith_org_member_details":            dataSourceMemberDetails(),
+			"cloudsmith_user_self":                     dataSourceUserSelf(),
+			"cloudsmith_usage_by_token":                dataSourceUsageByToken(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"cloudsmith_entitlement":               resourceEntitlement(),
+			"cloudsmith_entitlement_token_refresh": resourceEntitlementTokenRefresh(),
 			"cloudsmith_license_policy":            resourceLicensePolicy(),
 			"cloudsmith_repository":                resourceRepository(),
+			"cloudsmith_repository_ecdsa_key":      resourceRepositoryEcdsaKey(),
 			"cloudsmith_repository_geo_ip_rules":   resourceRepositoryGeoIpRules(),
+			"cloudsmith_repository_gpg_key":        resourceRepositoryGpgKey(),
 			"cloudsmith_repository_privileges":     resourceRepositoryPrivileges(),
 			"cloudsmith_repository_upstream":       resourceRepositoryUpstream(),
 			"cloudsmith_service":                   resourceService(),
 			"cloudsmith_team":                      resourceTeam(),
 			"cloudsmith_vulnerability_policy":      resourceVulnerabilityPolicy(),
 			"cloudsmith_webhook":                   resourceWebhook(),
+			"cloudsmith_package_copy":              resourcePackageCopy(),
 			"cloudsmith_package_deny_policy":       packageDenyPolicy(),
 			"cloudsmith_oidc":                      resourceOIDC(),
 			"cloudsmith_manage_team":               resourceManageTeam(),
-			"cloudsmith_saml":                      resourceSAML(),
+			"cloudsmith_saml":                      renamedResource(resourceSAML(), "cloudsmith_saml_group_sync"),
+			"cloudsmith_saml_group_sync":           resourceSAML(),
+			"cloudsmith_saml_group_syncs":          resourceSAMLGroupSyncs(),
 			"cloudsmith_repository_retention_rule": resourceRepoRetentionRule(),
 		},
 	}
 
-	p.ConfigureContextFunc = func(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	for _, r := range p.DataSourcesMap {
+		aliasNamespaceAttribute(r)
+		reportLowRateLimitBudget(r)
+	}
+
+	for _, r := range p.ResourcesMap {
+		aliasNamespaceAttribute(r)
+		tolerateSuspendedNamespaces(r)
+		reportLowRateLimitBudget(r)
+	}
+
+	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		terraformVersion := p.TerraformVersion
 		if terraformVersion == "" {
 			// Terraform 0.12 introduced this field to the protocol
@@ -71,8 +176,31 @@ func Provider() *schema.Provider {
 		apiHost := requiredString(d, "api_host")
 		apiKey := requiredString(d, "api_key")
 		userAgent := fmt.Sprintf("(%s %s) Terraform/%s", runtime.GOOS, runtime.GOARCH, terraformVersion)
+		if requiredBool(d, "disable_telemetry") {
+			// identify requests as coming from the provider, but nothing more
+			userAgent = anonymousUserAgent
+		}
+
+		httpOptions := httpClientOptions{
+			AdditionalRetryableStatusCodes: lo.Map(d.Get("additional_retryable_status_codes").(*schema.Set).List(), func(code interface{}, _ int) int {
+				return code.(int)
+			}),
+			Timeout:         time.Duration(d.Get("http_timeout").(int)) * time.Second,
+			MaxIdleConns:    d.Get("max_idle_conns").(int),
+			MaxConnsPerHost: d.Get("max_conns_per_host").(int),
+		}
+
+		config, diags := newProviderConfig(ctx, apiHost, apiKey, userAgent, httpOptions)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		config.ErrorOnDrift = requiredBool(d, "error_on_drift")
+		config.RedactSensitiveOutputs = requiredBool(d, "redact_sensitive_outputs")
+		config.ErrorOnSuspendedNamespace = requiredBool(d, "error_on_suspended_namespace")
+		config.AdoptExisting = requiredBool(d, "adopt_existing")
 
-		return newProviderConfig(apiHost, apiKey, userAgent)
+		return config, diags
 	}
 
 	return p
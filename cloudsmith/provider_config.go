@@ -4,29 +4,101 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/cloudsmith-io/cloudsmith-api-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 var errMissingCredentials = errors.New("credentials required for Cloudsmith provider")
 
+// redactedValue is stored in place of secrets which shouldn't end up in state.
+const redactedValue = "**redacted**"
+
 type providerConfig struct {
 	// authentication credentials for the configured user
 	Auth context.Context
 
 	// initialised Cloudsmith API client
 	APIClient *cloudsmith.APIClient
+
+	// services wrapping the API client, which can be replaced by fakes in tests
+	GeoIP GeoIPService
+	SAML  SAMLService
+	Repos ReposService
+
+	// fail reads which find attributes changed outside of Terraform
+	ErrorOnDrift bool
+
+	// replace sensitive values in data sources unless explicitly requested
+	RedactSensitiveOutputs bool
+
+	// fail rather than warn when refreshing resources in suspended namespaces
+	ErrorOnSuspendedNamespace bool
+
+	// adopt existing objects on create conflicts unless a resource's
+	// lifecycle_hint says otherwise
+	AdoptExisting bool
+
+	// rate limit reported by the API over the run of the provider
+	RateLimit *rateLimitBudget
+}
+
+// httpClientOptions tunes the HTTP client used to make API requests.
+type httpClientOptions struct {
+	// status codes to retry in addition to defaultRetryableStatusCodes
+	AdditionalRetryableStatusCodes []int
+
+	// time limit for each request, including retries; zero means no limit
+	Timeout time.Duration
+
+	// connection pool limits; zero means the net/http default
+	MaxIdleConns    int
+	MaxConnsPerHost int
+}
+
+// newHTTPTransport returns the transport used to connect to the API. Almost
+// all requests go to the same host, so the idle connection pool is shared
+// with that host rather than limited to net/http's default of two idle
+// connections per host, which would otherwise defeat keep-alive under
+// parallelism.
+func newHTTPTransport(httpOptions httpClientOptions) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if httpOptions.MaxIdleConns > 0 {
+		transport.MaxIdleConns = httpOptions.MaxIdleConns
+	}
+	transport.MaxIdleConnsPerHost = transport.MaxIdleConns
+	transport.MaxConnsPerHost = httpOptions.MaxConnsPerHost
+
+	return transport
 }
 
-func newProviderConfig(apiHost, apiKey, userAgent string) (*providerConfig, diag.Diagnostics) {
+func newProviderConfig(
+	ctx context.Context, apiHost, apiKey, userAgent string, httpOptions httpClientOptions,
+) (*providerConfig, diag.Diagnostics) {
 	if apiKey == "" {
 		return nil, diag.FromErr(errMissingCredentials)
 	}
 
-	httpClient := http.DefaultClient
-	httpClient.Transport = logging.NewSubsystemLoggingHTTPTransport("Cloudsmith", http.DefaultTransport)
+	rateLimit := &rateLimitBudget{}
+
+	metrics := newMetricsTransport(
+		ctx,
+		newRetryTransport(
+			ctx,
+			logging.NewSubsystemLoggingHTTPTransport("Cloudsmith", newHTTPTransport(httpOptions)),
+			httpOptions.AdditionalRetryableStatusCodes,
+		),
+		rateLimit,
+	)
+	if stopCtx, ok := ctx.Value(schema.StopContextKey).(context.Context); ok {
+		metrics.logFinalSummaryOnStop(stopCtx)
+	}
+
+	httpClient := &http.Client{Timeout: httpOptions.Timeout, Transport: metrics}
 
 	config := cloudsmith.NewConfiguration()
 	config.Debug = logging.IsDebugOrHigher()
@@ -46,7 +118,26 @@ func newProviderConfig(apiHost, apiKey, userAgent string) (*providerConfig, diag
 		},
 	)
 
-	return &providerConfig{Auth: auth, APIClient: apiClient}, nil
+	return &providerConfig{
+		Auth:      auth,
+		APIClient: apiClient,
+		GeoIP:     &apiGeoIPService{auth: auth, client: apiClient},
+		SAML:      &apiSAMLService{auth: auth, client: apiClient},
+		Repos:     &apiReposService{auth: auth, client: apiClient},
+		RateLimit: rateLimit,
+	}, nil
+}
+
+// sensitiveOutput returns the value to store in state for a sensitive data
+// source attribute: the value itself if it was explicitly requested or
+// redaction is disabled, otherwise nothing, so that a redacted value can't be
+// mistaken for (and used as) a real one.
+func (pc *providerConfig) sensitiveOutput(value string, requested bool) string {
+	if pc.RedactSensitiveOutputs && !requested {
+		return ""
+	}
+
+	return value
 }
 
 func (pc *providerConfig) GetAPIKey() string {
@@ -2,12 +2,20 @@
 package cloudsmith
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
+// testAccNamePrefix starts the names of resources created by acceptance
+// tests with random names.
+const testAccNamePrefix = "tf-acc-test"
+
 var (
 	testAccProviders map[string]*schema.Provider
 	testAccProvider  *schema.Provider
@@ -36,3 +44,52 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("CLOUDSMITH_NAMESPACE must be set for acceptance tests")
 	}
 }
+
+// testAccProviderConfig returns the configuration of a new provider,
+// configured from the environment like in acceptance tests, for tests which
+// call the API outside of a resource.Test run, e.g. to set up or clean up.
+func testAccProviderConfig(t *testing.T) *providerConfig {
+	t.Helper()
+
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{})); diags.HasError() {
+		t.Fatalf("unexpected error configuring the provider: %v", diags)
+	}
+	return p.Meta().(*providerConfig)
+}
+
+// testAccRandomName returns a name for resources created by an acceptance
+// test, randomized so that tests running in parallel, or resources left
+// behind by earlier runs, don't conflict. Names share the testAccNamePrefix.
+func testAccRandomName(name string) string {
+	return acctest.RandomWithPrefix(fmt.Sprintf("%s-%s", testAccNamePrefix, name))
+}
+
+// TestProviderConfigSensitiveOutput verifies that sensitive data source values
+// are left empty when redacted, unless explicitly requested.
+func TestProviderConfigSensitiveOutput(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		redact    bool
+		requested bool
+		want      string
+	}{
+		{"not redacted", false, false, "s3cr3t"},
+		{"redacted", true, false, ""},
+		{"redacted but requested", true, true, "s3cr3t"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			pc := &providerConfig{RedactSensitiveOutputs: tc.redact}
+			if got := pc.sensitiveOutput("s3cr3t", tc.requested); got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
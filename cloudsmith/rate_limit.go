@@ -0,0 +1,159 @@
+package cloudsmith
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Headers with which the API reports the rate limit of the authenticated user.
+const (
+	rateLimitLimitHeader     = "X-RateLimit-Limit"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// rateLimitLowFraction is the fraction of the rate limit below which the
+// remaining budget is reported as low.
+const rateLimitLowFraction = 0.1
+
+// rateLimitBudget tracks the rate limit reported by API responses over a run
+// of the provider, so that usage can be summarized and users warned before
+// requests start being throttled.
+type rateLimitBudget struct {
+	mu        sync.Mutex
+	observed  bool
+	limit     int64
+	remaining int64
+	reset     time.Time
+	warned    bool
+}
+
+// observe records the rate limit headers of a response, if it has any.
+// Concurrent responses may arrive out of order, so within a rate limit window
+// the lowest remaining count is kept.
+func (b *rateLimitBudget) observe(header http.Header) {
+	limit, err := strconv.ParseInt(header.Get(rateLimitLimitHeader), 10, 64)
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.ParseInt(header.Get(rateLimitRemainingHeader), 10, 64)
+	if err != nil {
+		return
+	}
+	var reset time.Time
+	if seconds, err := strconv.ParseFloat(header.Get(rateLimitResetHeader), 64); err == nil {
+		reset = time.Unix(int64(seconds), 0).UTC()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case !b.observed || reset.After(b.reset):
+		b.observed = true
+		b.limit = limit
+		b.remaining = remaining
+		b.reset = reset
+	case reset.Equal(b.reset) && remaining < b.remaining:
+		b.remaining = remaining
+	}
+}
+
+// summaryLocked describes the rate limit budget, e.g. "used 840/1000
+// requests, resets at 2023-01-02T15:04:05Z". Callers must hold b.mu.
+func (b *rateLimitBudget) summaryLocked() string {
+	summary := fmt.Sprintf("used %d/%d requests", b.limit-b.remaining, b.limit)
+	if !b.reset.IsZero() {
+		summary += fmt.Sprintf(", resets at %s", b.reset.Format(time.RFC3339))
+	}
+	return summary
+}
+
+// summary describes the rate limit budget, or returns false if no response
+// has reported it yet.
+func (b *rateLimitBudget) summary() (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.observed {
+		return "", false
+	}
+	return b.summaryLocked(), true
+}
+
+// lowBudgetWarning returns a warning the first time the remaining budget is
+// found to be below rateLimitLowFraction of the limit, and nothing otherwise.
+func (b *rateLimitBudget) lowBudgetWarning() diag.Diagnostics {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.observed || b.warned || float64(b.remaining) >= float64(b.limit)*rateLimitLowFraction {
+		return nil
+	}
+	b.warned = true
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Cloudsmith API rate limit nearly exhausted",
+		Detail: fmt.Sprintf(
+			"The Cloudsmith API rate limit has %s. Further requests may be throttled, slowing down "+
+				"this run. Consider lowering Terraform's -parallelism or splitting the configuration "+
+				"into smaller workspaces.",
+			b.summaryLocked(),
+		),
+	}}
+}
+
+// reportLowRateLimitBudget wraps the functions of a resource or data source
+// so that the first operation to run once the rate limit budget is low
+// returns a warning. Terraform has no hook to report anything at the end of a
+// run, so this is the only way to surface it other than in the logs.
+func reportLowRateLimitBudget(r *schema.Resource) {
+	wrap := func(f schema.ReadContextFunc) schema.ReadContextFunc {
+		if f == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			diags := f(ctx, d, m)
+			if pc, ok := m.(*providerConfig); ok && pc.RateLimit != nil {
+				diags = append(diags, pc.RateLimit.lowBudgetWarning()...)
+			}
+			return diags
+		}
+	}
+	legacy := func(f func(*schema.ResourceData, interface{}) error) schema.ReadContextFunc {
+		if f == nil {
+			return nil
+		}
+		return func(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			return diag.FromErr(f(d, m))
+		}
+	}
+
+	if r.Read != nil {
+		r.ReadContext, r.Read = legacy(r.Read), nil
+	}
+	r.ReadContext = wrap(r.ReadContext)
+
+	if r.Create != nil {
+		r.CreateContext, r.Create = schema.CreateContextFunc(legacy(r.Create)), nil
+	}
+	r.CreateContext = schema.CreateContextFunc(wrap(schema.ReadContextFunc(r.CreateContext)))
+
+	if r.Update != nil {
+		r.UpdateContext, r.Update = schema.UpdateContextFunc(legacy(r.Update)), nil
+	}
+	r.UpdateContext = schema.UpdateContextFunc(wrap(schema.ReadContextFunc(r.UpdateContext)))
+
+	if r.Delete != nil {
+		r.DeleteContext, r.Delete = schema.DeleteContextFunc(legacy(r.Delete)), nil
+	}
+	r.DeleteContext = schema.DeleteContextFunc(wrap(schema.ReadContextFunc(r.DeleteContext)))
+}
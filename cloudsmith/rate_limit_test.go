@@ -0,0 +1,86 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func rateLimitHeader(limit, remaining, reset string) http.Header {
+	header := http.Header{}
+	header.Set(rateLimitLimitHeader, limit)
+	header.Set(rateLimitRemainingHeader, remaining)
+	header.Set(rateLimitResetHeader, reset)
+	return header
+}
+
+// TestRateLimitBudget_observe verifies that the lowest remaining count of the
+// latest rate limit window is kept, whatever order responses arrive in.
+func TestRateLimitBudget_observe(t *testing.T) {
+	t.Parallel()
+
+	b := &rateLimitBudget{}
+
+	if _, ok := b.summary(); ok {
+		t.Fatal("expected no summary before any rate limit is observed")
+	}
+
+	b.observe(rateLimitHeader("1000", "170", "1672671845"))
+	b.observe(rateLimitHeader("1000", "160", "1672671845"))
+	b.observe(rateLimitHeader("1000", "165", "1672671845"))
+	b.observe(http.Header{})
+
+	want := "used 840/1000 requests, resets at 2023-01-02T15:04:05Z"
+	if got, _ := b.summary(); got != want {
+		t.Errorf("summary() = %q, want %q", got, want)
+	}
+
+	// a new window replaces the previous one
+	b.observe(rateLimitHeader("1000", "999", "1672675445"))
+
+	want = "used 1/1000 requests, resets at 2023-01-02T16:04:05Z"
+	if got, _ := b.summary(); got != want {
+		t.Errorf("summary() = %q, want %q", got, want)
+	}
+}
+
+// TestReportLowRateLimitBudget verifies that a warning is returned by the
+// first operation to run once the budget is low, and only by that one.
+func TestReportLowRateLimitBudget(t *testing.T) {
+	t.Parallel()
+
+	r := &schema.Resource{
+		Read: func(d *schema.ResourceData, m interface{}) error {
+			return nil
+		},
+		Schema: map[string]*schema.Schema{},
+	}
+	reportLowRateLimitBudget(r)
+
+	if r.Read != nil {
+		t.Fatal("expected Read to be replaced by ReadContext")
+	}
+
+	pc := &providerConfig{RateLimit: &rateLimitBudget{}}
+	read := func() diag.Diagnostics {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+		return r.ReadContext(context.Background(), d, pc)
+	}
+
+	pc.RateLimit.observe(rateLimitHeader("1000", "500", "1672671845"))
+	if diags := read(); len(diags) != 0 {
+		t.Fatalf("expected no diagnostics, got: %v", diags)
+	}
+
+	pc.RateLimit.observe(rateLimitHeader("1000", "50", "1672671845"))
+	if diags := read(); len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a single warning, got: %v", diags)
+	}
+	if diags := read(); len(diags) != 0 {
+		t.Fatalf("expected the warning to be returned once, got: %v", diags)
+	}
+}
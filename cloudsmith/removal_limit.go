@@ -0,0 +1,63 @@
+package cloudsmith
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// MaxRemovalsPerApply is the attribute with which resources managing an
+// exclusive set of grants (e.g. repository privileges or team members) cap how
+// many are removed by a single apply, so that a bad input (e.g. a data source
+// unexpectedly returning nothing) can't revoke all access at once.
+const MaxRemovalsPerApply = "max_removals_per_apply"
+
+// maxRemovalsPerApplySchema returns the schema of the MaxRemovalsPerApply
+// attribute for a resource managing the given kind of grant.
+func maxRemovalsPerApplySchema(grants string) *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeInt,
+		Description: fmt.Sprintf(
+			"The maximum number of %s removed by a single apply. Further removals are deferred to later applies, "+
+				"with a warning. Zero (the default) means no limit.", grants,
+		),
+		Optional:     true,
+		Default:      0,
+		ValidateFunc: validation.IntAtLeast(0),
+	}
+}
+
+// deferRemovals splits removals into those to make now and those deferred to
+// later applies by MaxRemovalsPerApply. Callers should sort removals first, so
+// that the same ones are deferred by every apply.
+func deferRemovals[T any](d *schema.ResourceData, removals []T) ([]T, []T) {
+	limit := d.Get(MaxRemovalsPerApply).(int)
+	if limit == 0 || len(removals) <= limit {
+		return removals, nil
+	}
+
+	return removals[:limit], removals[limit:]
+}
+
+// deferredRemovalsWarning returns a warning that some removals of the given
+// kind of grant have been deferred, or nothing if none were.
+func deferredRemovalsWarning(d *schema.ResourceData, grants string, deferred int) diag.Diagnostics {
+	if deferred == 0 {
+		return nil
+	}
+
+	limit := d.Get(MaxRemovalsPerApply).(int)
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Removal of %d %s deferred", deferred, grants),
+		Detail: fmt.Sprintf(
+			"Only %d %s can be removed per apply (%s), so %d were kept. They will be removed by later applies "+
+				"unless they are added back to the configuration. If so many removals are unexpected, check the "+
+				"inputs of the configuration before applying again.",
+			limit, grants, MaxRemovalsPerApply, deferred,
+		),
+	}}
+}
@@ -0,0 +1,112 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/samber/lo"
+)
+
+func TestDeferRemovals(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		limit    int
+		now      []string
+		deferred []string
+	}{
+		{"unlimited", 0, []string{"a", "b", "c"}, nil},
+		{"within limit", 3, []string{"a", "b", "c"}, nil},
+		{"over limit", 1, []string{"a"}, []string{"b", "c"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+				MaxRemovalsPerApply: maxRemovalsPerApplySchema("grants"),
+			}, map[string]interface{}{
+				MaxRemovalsPerApply: tc.limit,
+			})
+
+			now, deferred := deferRemovals(d, []string{"a", "b", "c"})
+			if !reflect.DeepEqual(now, tc.now) || !reflect.DeepEqual(deferred, tc.deferred) {
+				t.Errorf("deferRemovals() = (%v, %v), want (%v, %v)", now, deferred, tc.now, tc.deferred)
+			}
+
+			warning := deferredRemovalsWarning(d, "grants", len(deferred))
+			if (len(warning) > 0) != (len(tc.deferred) > 0) {
+				t.Errorf("expected a warning only if removals were deferred, got %v", warning)
+			}
+		})
+	}
+}
+
+// TestSAMLGroupSyncsMaxRemovalsPerApply removes every mapping of a
+// cloudsmith_saml_group_syncs resource with max_removals_per_apply set, and
+// verifies that only that many are deleted while the rest stay managed.
+func TestSAMLGroupSyncsMaxRemovalsPerApply(t *testing.T) {
+	t.Parallel()
+
+	saml := &fakeSAMLService{}
+	pc := &providerConfig{SAML: saml}
+	r := resourceSAMLGroupSyncs()
+
+	mapping := func(idpValue string) map[string]interface{} {
+		return map[string]interface{}{"idp_value": idpValue, "team": "dev", "role": "Member"}
+	}
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"organization":      "my-org",
+		"idp_key":           "groups",
+		"mapping":           []interface{}{mapping("a"), mapping("b"), mapping("c")},
+		MaxRemovalsPerApply: 1,
+	})
+	if diags := samlGroupSyncsReconcile(context.Background(), d, pc); diags.HasError() {
+		t.Fatalf("unexpected error creating: %v", diags)
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"organization":      "my-org",
+		"idp_key":           "groups",
+		"mapping":           []interface{}{mapping("c")},
+		MaxRemovalsPerApply: 1,
+	})
+	diff, err := r.Diff(context.Background(), d.State(), config, pc)
+	if err != nil {
+		t.Fatalf("unexpected error planning: %s", err)
+	}
+	d, err = schema.InternalMap(r.Schema).Data(d.State(), diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	diags := samlGroupSyncsReconcile(context.Background(), d, pc)
+	if diags.HasError() {
+		t.Fatalf("unexpected error updating: %v", diags)
+	}
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Errorf("expected a warning about the deferred removal, got %v", diags)
+	}
+
+	idpValues := lo.Map(saml.syncs, func(sync cloudsmith.OrganizationGroupSync, _ int) string {
+		return sync.IdpValue
+	})
+	sort.Strings(idpValues)
+	if want := []string{"b", "c"}; !reflect.DeepEqual(idpValues, want) {
+		t.Errorf("expected only mapping a to be deleted, got %v", idpValues)
+	}
+	if got := d.Get("mapping").(*schema.Set).Len(); got != 2 {
+		t.Errorf("expected the deferred mapping to stay in state, got %d mappings", got)
+	}
+}
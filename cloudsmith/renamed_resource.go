@@ -0,0 +1,21 @@
+package cloudsmith
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// renamedResource keeps a resource available under its old name after it has
+// been renamed, deprecating the old name. Both names share the same schema,
+// IDs and import format, so existing resources can be moved to the new name
+// by importing them under it and removing the old name from state, without
+// recreating them.
+func renamedResource(r *schema.Resource, newName string) *schema.Resource {
+	r.DeprecationMessage = fmt.Sprintf(
+		"this resource has been renamed to %s, which is identical; "+
+			"import existing resources under the new name and remove them from state under the old one",
+		newName,
+	)
+	return r
+}
@@ -0,0 +1,110 @@
+package cloudsmith
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const RepositorySlugPerm string = "repository_slug_perm"
+
+// slugPermPattern matches the format of Cloudsmith's immutable slug_perm
+// identifiers, e.g. "BWtoUzKJzRrA".
+var slugPermPattern = regexp.MustCompile(`^[a-zA-Z0-9]{12}$`)
+
+// isSlugPerm reports whether the given repository identifier is a slug_perm
+// rather than a slug. Slugs are always lower case, so anything containing
+// upper case characters in the slug_perm format must be a slug_perm.
+func isSlugPerm(value string) bool {
+	return slugPermPattern.MatchString(value) && value != strings.ToLower(value)
+}
+
+// resolveRepositorySlugPerm returns the slug_perm of a repository identified
+// by either its slug or its slug_perm. The API is only consulted when the
+// identifier cannot be recognised as a slug_perm from its format alone.
+func resolveRepositorySlugPerm(pc *providerConfig, namespace, repository string) (string, *http.Response, error) {
+	if isSlugPerm(repository) {
+		return repository, nil, nil
+	}
+
+	repo, resp, err := pc.Repos.Read(namespace, repository)
+	if err != nil {
+		return "", resp, err
+	}
+
+	return repo.GetSlugPerm(), resp, nil
+}
+
+// repositorySlugPerm returns the slug_perm of the repository a resource
+// belongs to, which is used when talking to the API so that the resource
+// survives the repository being renamed. If it isn't yet known (on create,
+// after import or after upgrading from an earlier provider version) it is
+// resolved from the repository attribute and stored in state.
+func repositorySlugPerm(pc *providerConfig, d *schema.ResourceData, namespace string) (string, *http.Response, error) {
+	return repositoryAttributeSlugPerm(pc, d, namespace, Repository, RepositorySlugPerm)
+}
+
+// repositoryAttributeSlugPerm is repositorySlugPerm for resources with more
+// than one repository attribute, each with its own slug_perm attribute.
+func repositoryAttributeSlugPerm(
+	pc *providerConfig, d *schema.ResourceData, namespace, repositoryKey, slugPermKey string,
+) (string, *http.Response, error) {
+	if slugPerm := d.Get(slugPermKey).(string); slugPerm != "" {
+		return slugPerm, nil, nil
+	}
+
+	slugPerm, resp, err := resolveRepositorySlugPerm(pc, namespace, requiredString(d, repositoryKey))
+	if err != nil {
+		return "", resp, err
+	}
+
+	d.Set(slugPermKey, slugPerm)
+
+	return slugPerm, resp, nil
+}
+
+// customizeDiffRepository replaces the resource when its repository attribute
+// changes, unless the old and new values are the slug and slug_perm (or old
+// and new slugs, following a rename) of the same repository.
+func customizeDiffRepository(namespaceKey string) schema.CustomizeDiffFunc {
+	return customizeDiffRepositoryAttribute(namespaceKey, Repository, RepositorySlugPerm)
+}
+
+// customizeDiffRepositoryAttribute is customizeDiffRepository for resources
+// with more than one repository attribute, each with its own slug_perm
+// attribute.
+func customizeDiffRepositoryAttribute(namespaceKey, repositoryKey, slugPermKey string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if d.Id() == "" || !d.HasChange(repositoryKey) {
+			return nil
+		}
+
+		if !d.NewValueKnown(repositoryKey) {
+			return d.ForceNew(repositoryKey)
+		}
+
+		pc := m.(*providerConfig)
+
+		// any failure to resolve the new value (e.g. the repository doesn't
+		// exist yet) means it can't be the same repository
+		slugPerm, _, err := resolveRepositorySlugPerm(pc, d.Get(namespaceKey).(string), d.Get(repositoryKey).(string))
+		if err != nil || slugPerm != d.Get(slugPermKey).(string) {
+			return d.ForceNew(repositoryKey)
+		}
+
+		return nil
+	}
+}
+
+// repositorySlugPermSchema returns the schema of the computed attribute
+// holding the slug_perm of the repository a resource belongs to.
+func repositorySlugPermSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The slug_perm of the repository, used to identify it regardless of renames.",
+		Computed:    true,
+	}
+}
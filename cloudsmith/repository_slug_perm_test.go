@@ -0,0 +1,25 @@
+//nolint:testpackage
+package cloudsmith
+
+import "testing"
+
+func TestIsSlugPerm(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]bool{
+		"BWtoUzKJzRrA":     true,
+		"a1B2c3D4e5F6":     true,
+		"my-repository":    false,
+		"myrepository":     false,
+		"terraform-acc-01": false,
+		"BWtoUzKJzRr":      false,
+		"BWtoUzKJzRrA1":    false,
+		"":                 false,
+	}
+
+	for value, want := range testCases {
+		if got := isSlugPerm(value); got != want {
+			t.Errorf("isSlugPerm(%q) = %t, want %t", value, got, want)
+		}
+	}
+}
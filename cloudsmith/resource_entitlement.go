@@ -11,6 +11,32 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+var (
+	entitlementBandwidthUnits = []string{
+		"Byte",
+		"Kilobyte",
+		"Megabyte",
+		"Gigabyte",
+		"Terabyte",
+		"Petabyte",
+		"Exabyte",
+		"Zettabyte",
+		"Yottabyte",
+	}
+
+	entitlementResetPeriods = []string{
+		"Never Reset",
+		"Daily",
+		"Weekly",
+		"Fortnightly",
+		"Monthly",
+		"Bi-Monthly",
+		"Quarterly",
+		"Every 6 months",
+		"Annual",
+	}
+)
+
 func importEntitlement(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	idParts := strings.Split(d.Id(), ".")
 	if len(idParts) != 3 {
@@ -29,19 +55,25 @@ func resourceEntitlementCreate(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, "namespace")
-	repository := requiredString(d, "repository")
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
 
 	req := pc.APIClient.EntitlementsApi.EntitlementsCreate(pc.Auth, namespace, repository)
 	req = req.Data(cloudsmith.RepositoryTokenRequest{
-		IsActive:           optionalBool(d, "is_active"),
-		LimitDateRangeFrom: nullableTime(d, "limit_date_range_from"),
-		LimitDateRangeTo:   nullableTime(d, "limit_date_range_to"),
-		LimitNumClients:    nullableInt64(d, "limit_num_clients"),
-		LimitNumDownloads:  nullableInt64(d, "limit_num_downloads"),
-		LimitPackageQuery:  nullableString(d, "limit_package_query"),
-		LimitPathQuery:     nullableString(d, "limitPathQuery"),
-		Name:               requiredString(d, "name"),
-		Token:              optionalString(d, "token"),
+		IsActive:             optionalBool(d, "is_active"),
+		LimitBandwidth:       nullableInt64(d, "limit_bandwidth"),
+		LimitBandwidthUnit:   nullableString(d, "limit_bandwidth_unit"),
+		LimitDateRangeFrom:   nullableTime(d, "limit_date_range_from"),
+		LimitDateRangeTo:     nullableTime(d, "limit_date_range_to"),
+		LimitNumClients:      nullableInt64(d, "limit_num_clients"),
+		LimitNumDownloads:    nullableInt64(d, "limit_num_downloads"),
+		LimitPackageQuery:    nullableString(d, "limit_package_query"),
+		LimitPathQuery:       nullableString(d, "limit_path_query"),
+		Name:                 requiredString(d, "name"),
+		ScheduledResetPeriod: nullableString(d, "scheduled_reset_period"),
+		Token:                optionalString(d, "token"),
 	})
 	req = req.ShowTokens(true)
 
@@ -73,7 +105,15 @@ func resourceEntitlementRead(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, "namespace")
-	repository := requiredString(d, "repository")
+	repository, resp, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		if is404(resp) {
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
 
 	req := pc.APIClient.EntitlementsApi.EntitlementsRead(pc.Auth, namespace, repository, d.Id())
 	req = req.ShowTokens(true)
@@ -89,6 +129,8 @@ func resourceEntitlementRead(d *schema.ResourceData, m interface{}) error {
 	}
 
 	d.Set("is_active", entitlement.GetIsActive())
+	d.Set("limit_bandwidth", entitlement.GetLimitBandwidth())
+	d.Set("limit_bandwidth_unit", entitlement.GetLimitBandwidthUnit())
 	d.Set("limit_date_range_from", timeToString(entitlement.GetLimitDateRangeFrom()))
 	d.Set("limit_date_range_to", timeToString(entitlement.GetLimitDateRangeTo()))
 	d.Set("limit_num_clients", entitlement.GetLimitNumClients())
@@ -96,13 +138,18 @@ func resourceEntitlementRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("limit_package_query", entitlement.GetLimitPackageQuery())
 	d.Set("limit_path_query", entitlement.GetLimitPathQuery())
 	d.Set("name", entitlement.GetName())
+	d.Set("scheduled_reset_at", timeToString(entitlement.GetScheduledResetAt()))
+	d.Set("scheduled_reset_period", entitlement.GetScheduledResetPeriod())
 	d.Set("token", entitlement.GetToken())
 
 	// namespace and repository are not returned from the entitlement read
-	// endpoint, so we can use the values stored in resource state. We rely on
-	// ForceNew to ensure if either changes a new resource is created.
+	// endpoint, so we can use the values stored in resource state. Changing
+	// namespace forces a new resource; requests are made with
+	// repository_slug_perm, so repository is kept as configured and only
+	// forces a new resource if it refers to another repository (see
+	// customizeDiffRepository).
 	d.Set("namespace", namespace)
-	d.Set("repository", repository)
+	d.Set("repository", requiredString(d, "repository"))
 
 	return nil
 }
@@ -111,19 +158,25 @@ func resourceEntitlementUpdate(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, "namespace")
-	repository := requiredString(d, "repository")
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
 
 	req := pc.APIClient.EntitlementsApi.EntitlementsPartialUpdate(pc.Auth, namespace, repository, d.Id())
 	req = req.Data(cloudsmith.RepositoryTokenRequestPatch{
-		IsActive:           optionalBool(d, "is_active"),
-		LimitDateRangeFrom: nullableTime(d, "limit_date_range_from"),
-		LimitDateRangeTo:   nullableTime(d, "limit_date_range_to"),
-		LimitNumClients:    nullableInt64(d, "limit_num_clients"),
-		LimitNumDownloads:  nullableInt64(d, "limit_num_downloads"),
-		LimitPackageQuery:  nullableString(d, "limit_package_query"),
-		LimitPathQuery:     nullableString(d, "limit_path_query"),
-		Name:               optionalString(d, "name"),
-		Token:              optionalString(d, "token"),
+		IsActive:             optionalBool(d, "is_active"),
+		LimitBandwidth:       nullableInt64(d, "limit_bandwidth"),
+		LimitBandwidthUnit:   nullableString(d, "limit_bandwidth_unit"),
+		LimitDateRangeFrom:   nullableTime(d, "limit_date_range_from"),
+		LimitDateRangeTo:     nullableTime(d, "limit_date_range_to"),
+		LimitNumClients:      nullableInt64(d, "limit_num_clients"),
+		LimitNumDownloads:    nullableInt64(d, "limit_num_downloads"),
+		LimitPackageQuery:    nullableString(d, "limit_package_query"),
+		LimitPathQuery:       nullableString(d, "limit_path_query"),
+		Name:                 optionalString(d, "name"),
+		ScheduledResetPeriod: nullableString(d, "scheduled_reset_period"),
+		Token:                optionalString(d, "token"),
 	})
 	req = req.ShowTokens(true)
 
@@ -151,10 +204,13 @@ func resourceEntitlementDelete(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, "namespace")
-	repository := requiredString(d, "repository")
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
 
 	req := pc.APIClient.EntitlementsApi.EntitlementsDelete(pc.Auth, namespace, repository, d.Id())
-	_, err := pc.APIClient.EntitlementsApi.EntitlementsDeleteExecute(req)
+	_, err = pc.APIClient.EntitlementsApi.EntitlementsDeleteExecute(req)
 	if err != nil {
 		return err
 	}
@@ -184,6 +240,8 @@ func resourceEntitlement() *schema.Resource {
 		Update: resourceEntitlementUpdate,
 		Delete: resourceEntitlementDelete,
 
+		CustomizeDiff: customizeDiffRepository("namespace"),
+
 		Importer: &schema.ResourceImporter{
 			StateContext: importEntitlement,
 		},
@@ -195,17 +253,36 @@ func resourceEntitlement() *schema.Resource {
 				Optional:    true,
 				Computed:    true,
 			},
-			"limit_date_range_from": {
-				Type:         schema.TypeString,
-				Description:  "The starting date/time the token is allowed to be used from.",
+			"limit_bandwidth": {
+				Type: schema.TypeInt,
+				Description: "The maximum download bandwidth allowed for the token, expressed in " +
+					"limit_bandwidth_unit. Please note that since downloads are calculated " +
+					"asynchronously (after the download happens), the limit may not be imposed " +
+					"immediately but at a later point.",
 				Optional:     true,
-				ValidateFunc: validation.IsRFC3339Time,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
 			},
-			"limit_date_range_to": {
+			"limit_bandwidth_unit": {
 				Type:         schema.TypeString,
-				Description:  "The ending date/time the token is allowed to be used until.",
+				Description:  "The unit in which limit_bandwidth is expressed.",
 				Optional:     true,
-				ValidateFunc: validation.IsRFC3339Time,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(entitlementBandwidthUnits, false),
+			},
+			"limit_date_range_from": {
+				Type:             schema.TypeString,
+				Description:      "The starting date/time the token is allowed to be used from.",
+				Optional:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: suppressEquivalentTimes,
+			},
+			"limit_date_range_to": {
+				Type:             schema.TypeString,
+				Description:      "The ending date/time the token is allowed to be used until.",
+				Optional:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: suppressEquivalentTimes,
 			},
 			"limit_num_clients": {
 				Type: schema.TypeInt,
@@ -229,7 +306,8 @@ func resourceEntitlement() *schema.Resource {
 					"This uses the same syntax as the standard search used for repositories, and " +
 					"also supports boolean logic operators such as OR/AND/NOT and parentheses for " +
 					"grouping. This will still allow access to non-package files, such as metadata.",
-				Optional: true,
+				Optional:         true,
+				ValidateDiagFunc: validatePackageQuery,
 			},
 			"limit_path_query": {
 				Type: schema.TypeString,
@@ -255,11 +333,23 @@ func resourceEntitlement() *schema.Resource {
 			},
 			"repository": {
 				Type:         schema.TypeString,
-				Description:  "Repository to which this entitlement belongs.",
+				Description:  "Repository to which this entitlement belongs, identified by its slug or slug_perm.",
 				Required:     true,
-				ForceNew:     true,
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
+			RepositorySlugPerm: repositorySlugPermSchema(),
+			"scheduled_reset_at": {
+				Type:        schema.TypeString,
+				Description: "The time at which the token limits will next be automatically reset.",
+				Computed:    true,
+			},
+			"scheduled_reset_period": {
+				Type:         schema.TypeString,
+				Description:  "The period after which the token limits are automatically reset.",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(entitlementResetPeriods, false),
+			},
 			"token": {
 				Type:         schema.TypeString,
 				Description:  "The literal value of the token to be created.",
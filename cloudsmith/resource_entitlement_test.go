@@ -37,6 +37,10 @@ func TestAccEntitlement_basic(t *testing.T) {
 					testAccEntitlementCheckExists("cloudsmith_entitlement.test"),
 					resource.TestCheckResourceAttr("cloudsmith_entitlement.test", "name", "Test Entitlement Update"),
 					resource.TestCheckResourceAttr("cloudsmith_entitlement.test", "limit_num_downloads", "100"),
+					resource.TestCheckResourceAttr("cloudsmith_entitlement.test", "limit_bandwidth", "5"),
+					resource.TestCheckResourceAttr("cloudsmith_entitlement.test", "limit_bandwidth_unit", "Gigabyte"),
+					resource.TestCheckResourceAttr("cloudsmith_entitlement.test", "scheduled_reset_period", "Monthly"),
+					resource.TestCheckResourceAttrSet("cloudsmith_entitlement.test", "scheduled_reset_at"),
 				),
 			},
 			{
@@ -146,9 +150,13 @@ resource "cloudsmith_repository" "test" {
 }
 
 resource "cloudsmith_entitlement" "test" {
-	name                = "Test Entitlement Update"
-    limit_num_downloads = 100
-    namespace           = "${cloudsmith_repository.test.namespace}"
-    repository          = "${cloudsmith_repository.test.slug_perm}"
+	name                   = "Test Entitlement Update"
+    limit_bandwidth        = 5
+    limit_bandwidth_unit   = "Gigabyte"
+    limit_date_range_from  = "2030-01-01T01:00:00+01:00"
+    limit_num_downloads    = 100
+    namespace              = "${cloudsmith_repository.test.namespace}"
+    repository             = "${cloudsmith_repository.test.slug_perm}"
+    scheduled_reset_period = "Monthly"
 }
 `, os.Getenv("CLOUDSMITH_NAMESPACE"))
@@ -0,0 +1,140 @@
+package cloudsmith
+
+import (
+	"fmt"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceEntitlementTokenRefreshCreate regenerates the value of an existing
+// entitlement token. This is the only API call the resource makes: changing
+// any of its arguments (including triggers) replaces it, refreshing the token
+// again, except for repository referring to the same repository by another
+// name.
+func resourceEntitlementTokenRefreshCreate(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+	repository := requiredString(d, "repository")
+	entitlement := requiredString(d, "entitlement")
+
+	slugPerm, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return fmt.Errorf("error retrieving repository %s.%s: %w", namespace, repository, err)
+	}
+
+	req := pc.APIClient.EntitlementsApi.EntitlementsRefresh(pc.Auth, namespace, slugPerm, entitlement)
+	req = req.Data(cloudsmith.RepositoryTokenRefreshRequest{})
+	req = req.ShowTokens(true)
+
+	refreshed, _, err := pc.APIClient.EntitlementsApi.EntitlementsRefreshExecute(req)
+	if err != nil {
+		return fmt.Errorf("error refreshing entitlement token %s: %w", entitlement, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s.%s", namespace, repository, entitlement))
+	d.Set("token", refreshed.GetToken())
+	d.Set("refreshed_at", timeToString(refreshed.GetUpdatedAt()))
+
+	return resourceEntitlementTokenRefreshRead(d, m)
+}
+
+func resourceEntitlementTokenRefreshRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+	entitlement := requiredString(d, "entitlement")
+
+	repository, resp, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		if is404(resp) {
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	// the token itself is owned by the entitlement, so all there is to check
+	// is that it still exists
+	req := pc.APIClient.EntitlementsApi.EntitlementsRead(pc.Auth, namespace, repository, entitlement)
+	if _, resp, err := pc.APIClient.EntitlementsApi.EntitlementsReadExecute(req); err != nil {
+		if is404(resp) {
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// resourceEntitlementTokenRefreshUpdate only needs to handle repository being
+// changed to another name of the same repository; all other changes replace
+// the resource.
+func resourceEntitlementTokenRefreshUpdate(d *schema.ResourceData, m interface{}) error {
+	return resourceEntitlementTokenRefreshRead(d, m)
+}
+
+// resourceEntitlementTokenRefreshDelete only removes the resource from state;
+// the entitlement token keeps its current value.
+func resourceEntitlementTokenRefreshDelete(d *schema.ResourceData, m interface{}) error {
+	return nil
+}
+
+//nolint:funlen
+func resourceEntitlementTokenRefresh() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceEntitlementTokenRefreshCreate,
+		Read:   resourceEntitlementTokenRefreshRead,
+		Update: resourceEntitlementTokenRefreshUpdate,
+		Delete: resourceEntitlementTokenRefreshDelete,
+
+		CustomizeDiff: customizeDiffRepository("namespace"),
+
+		Schema: map[string]*schema.Schema{
+			"entitlement": {
+				Type:         schema.TypeString,
+				Description:  "The slug_perm of the entitlement token to refresh.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace to which the entitlement token belongs.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"refreshed_at": {
+				Type:        schema.TypeString,
+				Description: "ISO 8601 timestamp at which the entitlement token was refreshed.",
+				Computed:    true,
+			},
+			"repository": {
+				Type:         schema.TypeString,
+				Description:  "Repository to which the entitlement token belongs.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			RepositorySlugPerm: repositorySlugPermSchema(),
+			"token": {
+				Type:        schema.TypeString,
+				Description: "The new value of the entitlement token.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Description: "Arbitrary values which, when changed, cause the entitlement token to be refreshed again.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
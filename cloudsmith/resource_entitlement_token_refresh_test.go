@@ -0,0 +1,159 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestEntitlementTokenRefreshRepositoryChange verifies that changing the
+// repository only refreshes the token again when it refers to another
+// repository, and not when the same repository is referred to by its
+// slug_perm or by a new slug following a rename.
+func TestEntitlementTokenRefreshRepositoryChange(t *testing.T) {
+	t.Parallel()
+
+	pc := &providerConfig{
+		Repos: &fakeReposService{repositories: map[string][]cloudsmith.Repository{
+			"my-org": {
+				{Slug: cloudsmith.PtrString("my-renamed-repo"), SlugPerm: cloudsmith.PtrString("AbCdEfGhIjKl")},
+				{Slug: cloudsmith.PtrString("other-repo"), SlugPerm: cloudsmith.PtrString("MnOpQrStUvWx")},
+			},
+		}},
+	}
+
+	testCases := []struct {
+		name       string
+		repository string
+		replaced   bool
+	}{
+		{"slug_perm", "AbCdEfGhIjKl", false},
+		{"renamed", "my-renamed-repo", false},
+		{"other repository", "other-repo", true},
+		{"missing repository", "missing-repo", true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := resourceEntitlementTokenRefresh()
+
+			config := func(repository string) map[string]interface{} {
+				return map[string]interface{}{
+					"namespace":   "my-org",
+					"repository":  repository,
+					"entitlement": "YzAbCdEfGhIj",
+				}
+			}
+
+			d := schema.TestResourceDataRaw(t, r.Schema, config("my-repo"))
+			d.SetId("my-org.my-repo.YzAbCdEfGhIj")
+			d.Set(RepositorySlugPerm, "AbCdEfGhIjKl")
+
+			diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config(tc.repository)), pc)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if replaced := diff.RequiresNew(); replaced != tc.replaced {
+				t.Errorf("expected the token to be refreshed again: %t, got %t", tc.replaced, replaced)
+			}
+		})
+	}
+}
+
+// TestAccEntitlementTokenRefresh_basic creates an entitlement, refreshes its
+// token and verifies the value has changed, then changes the triggers and
+// verifies the token is refreshed again.
+func TestAccEntitlementTokenRefresh_basic(t *testing.T) {
+	t.Parallel()
+
+	var originalToken, firstToken string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccEntitlementTokenRefreshConfig, os.Getenv("CLOUDSMITH_NAMESPACE"), "one"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccEntitlementTokenRefreshCaptureToken("cloudsmith_entitlement.test", &originalToken),
+					testAccEntitlementTokenRefreshCheckChanged("cloudsmith_entitlement_token_refresh.test", &originalToken),
+					testAccEntitlementTokenRefreshCaptureToken("cloudsmith_entitlement_token_refresh.test", &firstToken),
+					resource.TestCheckResourceAttrSet("cloudsmith_entitlement_token_refresh.test", "refreshed_at"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccEntitlementTokenRefreshConfig, os.Getenv("CLOUDSMITH_NAMESPACE"), "two"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccEntitlementTokenRefreshCheckChanged("cloudsmith_entitlement_token_refresh.test", &firstToken),
+				),
+			},
+		},
+	})
+}
+
+//nolint:goerr113
+func testAccEntitlementTokenRefreshCaptureToken(resourceName string, token *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		*token = resourceState.Primary.Attributes["token"]
+		if *token == "" {
+			return fmt.Errorf("token not set: %s", resourceName)
+		}
+
+		return nil
+	}
+}
+
+//nolint:goerr113
+func testAccEntitlementTokenRefreshCheckChanged(resourceName string, previous *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		if token := resourceState.Primary.Attributes["token"]; token == *previous {
+			return fmt.Errorf("token was not refreshed: %s", resourceName)
+		}
+
+		return nil
+	}
+}
+
+var testAccEntitlementTokenRefreshConfig = `
+resource "cloudsmith_repository" "test" {
+	name      = "terraform-acc-test-ent-refresh"
+	namespace = "%s"
+}
+
+resource "cloudsmith_entitlement" "test" {
+	name       = "Test Entitlement Refresh"
+	namespace  = cloudsmith_repository.test.namespace
+	repository = cloudsmith_repository.test.slug_perm
+}
+
+resource "cloudsmith_entitlement_token_refresh" "test" {
+	namespace   = cloudsmith_entitlement.test.namespace
+	repository  = cloudsmith_entitlement.test.repository
+	entitlement = cloudsmith_entitlement.test.id
+
+	triggers = {
+		rotation = "%s"
+	}
+}
+`
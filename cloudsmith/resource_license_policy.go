@@ -237,9 +237,10 @@ func resourceLicensePolicy() *schema.Resource {
 				Required: true,
 			},
 			PackageQueryString: {
-				Type:        schema.TypeString,
-				Description: "A search / filter string of packages to include in the policy.",
-				Optional:    true,
+				Type:             schema.TypeString,
+				Description:      "A search / filter string of packages to include in the policy.",
+				Optional:         true,
+				ValidateDiagFunc: validatePackageQuery,
 			},
 			UpdatedAt: {
 				Type:        schema.TypeString,
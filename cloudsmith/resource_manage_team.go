@@ -3,10 +3,13 @@ package cloudsmith
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/samber/lo"
 )
 
 // The purpose of this resource is to add/remove users from a team in Cloudsmith
@@ -21,6 +24,7 @@ func importManageTeam(ctx context.Context, d *schema.ResourceData, m interface{}
 
 	d.Set("organization", idParts[0])
 	d.Set("team_name", idParts[1])
+	d.Set(MaxRemovalsPerApply, 0)
 	return []*schema.ResourceData{d}, nil
 }
 
@@ -30,18 +34,8 @@ func resourceManageTeamAdd(d *schema.ResourceData, m interface{}) error {
 	organization := requiredString(d, "organization")
 	teamName := requiredString(d, "team_name")
 
-	teamMembers := d.Get("members").([]interface{})
-	teamMembersList := make([]cloudsmith.OrganizationTeamMembership, len(teamMembers))
-	for i, v := range teamMembers {
-		teamMember := v.(map[string]interface{})
-		teamMembersList[i] = cloudsmith.OrganizationTeamMembership{
-			Role: teamMember["role"].(string),
-			User: teamMember["user"].(string),
-		}
-	}
-
 	teamMembersData := cloudsmith.OrganizationTeamMembers{
-		Members: teamMembersList,
+		Members: expandTeamMembers(d.Get("members").([]interface{})),
 	}
 
 	req := pc.APIClient.OrgsApi.OrgsTeamsMembersCreate(pc.Auth, organization, teamName)
@@ -57,37 +51,74 @@ func resourceManageTeamAdd(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
+// expandTeamMembers converts "members" blocks to a slice of memberships.
+func expandTeamMembers(members []interface{}) []cloudsmith.OrganizationTeamMembership {
+	return lo.Map(members, func(x interface{}, index int) cloudsmith.OrganizationTeamMembership {
+		teamMember := x.(map[string]interface{})
+		return cloudsmith.OrganizationTeamMembership{
+			Role: teamMember["role"].(string),
+			User: teamMember["user"].(string),
+		}
+	})
+}
+
+// replaceTeamMembers replaces the members of a team.
+func replaceTeamMembers(pc *providerConfig, organization, teamName string, members []cloudsmith.OrganizationTeamMembership) error {
+	req := pc.APIClient.OrgsApi.OrgsTeamsMembersUpdate(pc.Auth, organization, teamName)
+	req = req.Data(cloudsmith.OrganizationTeamMembers{
+		Members: members,
+	})
+
+	_, _, err := pc.APIClient.OrgsApi.OrgsTeamsMembersUpdateExecute(req)
+	return err
+}
+
 // We're using the replace members endpoint here so we need to compare the existing members with the new members and adjust the delta
 func resourceManageTeamUpdateRemove(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 	organization := requiredString(d, "organization")
 	teamName := requiredString(d, "team_name")
 
-	teamMembers := d.Get("members").([]interface{})
-	teamMembersList := make([]cloudsmith.OrganizationTeamMembership, len(teamMembers))
-	for i, v := range teamMembers {
-		teamMember := v.(map[string]interface{})
-		teamMembersList[i] = cloudsmith.OrganizationTeamMembership{
-			Role: teamMember["role"].(string),
-			User: teamMember["user"].(string),
-		}
+	if err := replaceTeamMembers(pc, organization, teamName, expandTeamMembers(d.Get("members").([]interface{}))); err != nil {
+		return err
 	}
 
-	teamMembersData := cloudsmith.OrganizationTeamMembers{
-		Members: teamMembersList,
-	}
+	d.SetId(fmt.Sprintf("%s.%s", organization, teamName))
 
-	req := pc.APIClient.OrgsApi.OrgsTeamsMembersUpdate(pc.Auth, organization, teamName)
-	req = req.Data(teamMembersData)
+	return nil
+}
 
-	_, _, err := pc.APIClient.OrgsApi.OrgsTeamsMembersUpdateExecute(req)
-	if err != nil {
-		return err
+// resourceManageTeamUpdate replaces the members of the team, keeping members
+// whose removal is deferred by max_removals_per_apply.
+func resourceManageTeamUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	pc := m.(*providerConfig)
+	organization := requiredString(d, "organization")
+	teamName := requiredString(d, "team_name")
+
+	oldMembers, newMembers := d.GetChange("members")
+	members := expandTeamMembers(newMembers.([]interface{}))
+
+	users := lo.Map(members, func(member cloudsmith.OrganizationTeamMembership, index int) string {
+		return member.User
+	})
+	removed := lo.Filter(expandTeamMembers(oldMembers.([]interface{})), func(member cloudsmith.OrganizationTeamMembership, index int) bool {
+		return !lo.Contains(users, member.User)
+	})
+	sort.Slice(removed, func(i, j int) bool {
+		return removed[i].User < removed[j].User
+	})
+
+	_, deferred := deferRemovals(d, removed)
+	members = append(members, deferred...)
+
+	if err := replaceTeamMembers(pc, organization, teamName, members); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(fmt.Sprintf("%s.%s", organization, teamName))
 
-	return nil
+	diags := deferredRemovalsWarning(d, "team members", len(deferred))
+	return append(diags, diag.FromErr(resourceManageTeamRead(d, m))...)
 }
 
 func resourceManageTeamRead(d *schema.ResourceData, m interface{}) error {
@@ -128,15 +159,16 @@ func resourceManageTeamRead(d *schema.ResourceData, m interface{}) error {
 
 func resourceManageTeam() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceManageTeamAdd,
-		Read:   resourceManageTeamRead,
-		Update: resourceManageTeamUpdateRemove,
-		Delete: resourceManageTeamUpdateRemove,
+		Create:        resourceManageTeamAdd,
+		Read:          resourceManageTeamRead,
+		UpdateContext: resourceManageTeamUpdate,
+		Delete:        resourceManageTeamUpdateRemove,
 		Importer: &schema.ResourceImporter{
 			StateContext: importManageTeam,
 		},
 
 		Schema: map[string]*schema.Schema{
+			MaxRemovalsPerApply: maxRemovalsPerApplySchema("team members"),
 			"organization": {
 				Type:     schema.TypeString,
 				Required: true,
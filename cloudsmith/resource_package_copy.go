@@ -0,0 +1,367 @@
+package cloudsmith
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
+)
+
+// DestinationSlugPerm holds the slug_perm of the repository a
+// cloudsmith_package_copy copies to, like RepositorySlugPerm does for the
+// repository it copies from.
+const DestinationSlugPerm string = "destination_slug_perm"
+
+const (
+	packageCopySyncTimeout  = time.Minute * 20
+	packageCopySyncInterval = time.Second * 5
+)
+
+// findPackageCopySource returns the single package in a repository matched by
+// a package query, which is the package a cloudsmith_package_copy copies.
+func findPackageCopySource(pc *providerConfig, namespace, repository, query string) (*cloudsmith.Package, error) {
+	packages, err := retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.Package, *http.Response, error) {
+		req := pc.APIClient.PackagesApi.PackagesList(pc.Auth, namespace, repository)
+		req = req.Page(page)
+		req = req.PageSize(pageSize)
+		req = req.Query(query)
+		return pc.APIClient.PackagesApi.PackagesListExecute(req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(packages) {
+	case 0:
+		return nil, fmt.Errorf("no package matching '%s' found in repository %s.%s", query, namespace, repository)
+	case 1:
+		return &packages[0], nil
+	default:
+		return nil, fmt.Errorf(
+			"%d packages matching '%s' found in repository %s.%s, the query must match exactly one package",
+			len(packages), query, namespace, repository,
+		)
+	}
+}
+
+func resourcePackageCopyCreate(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+	repository := requiredString(d, "repository")
+	destination := requiredString(d, "destination")
+
+	sourceSlugPerm, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return fmt.Errorf("error retrieving repository %s.%s: %w", namespace, repository, err)
+	}
+	destinationSlugPerm, _, err := repositoryAttributeSlugPerm(pc, d, namespace, "destination", DestinationSlugPerm)
+	if err != nil {
+		return fmt.Errorf("error retrieving repository %s.%s: %w", namespace, destination, err)
+	}
+
+	source, err := findPackageCopySource(pc, namespace, sourceSlugPerm, requiredString(d, "package_query"))
+	if err != nil {
+		return err
+	}
+
+	req := pc.APIClient.PackagesApi.PackagesCopy(pc.Auth, namespace, sourceSlugPerm, source.GetSlugPerm())
+	req = req.Data(cloudsmith.PackageCopyRequest{
+		Destination: destinationSlugPerm,
+		Republish:   optionalBool(d, "republish"),
+	})
+
+	copied, _, err := pc.APIClient.PackagesApi.PackagesCopyExecute(req)
+	if err != nil {
+		return fmt.Errorf("error copying package %s to %s: %w", source.GetSlugPerm(), destination, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s.%s", namespace, destination, copied.GetSlugPerm()))
+	d.Set("package", copied.GetSlugPerm())
+	d.Set("source_package", source.GetSlugPerm())
+	d.Set("source_digest", source.GetChecksumSha256())
+
+	// the copy can only be tagged once it has synced
+	preserveMetadata := requiredBool(d, "preserve_metadata")
+	if requiredBool(d, "wait_for_target_sync") || preserveMetadata {
+		if err := waitForPackageSync(pc, namespace, destinationSlugPerm, copied.GetSlugPerm()); err != nil {
+			return fmt.Errorf("error waiting for package (%s) to sync in %s: %w", copied.GetSlugPerm(), destination, err)
+		}
+	}
+
+	if preserveMetadata {
+		if err := preservePackageTags(pc, namespace, destinationSlugPerm, copied.GetSlugPerm(), source); err != nil {
+			return fmt.Errorf("error tagging package (%s) in %s: %w", copied.GetSlugPerm(), destination, err)
+		}
+	}
+
+	return resourcePackageCopyRead(d, m)
+}
+
+// packageInfoTags returns the user-defined ("info") tags of a package, sorted,
+// from its tags grouped by type as returned by the API. Tags of other types
+// (e.g. version) are generated by Cloudsmith for every package.
+func packageInfoTags(tags map[string]interface{}) []string {
+	info, ok := tags["info"].([]interface{})
+	if !ok {
+		return []string{}
+	}
+
+	infoTags := lo.FilterMap(info, func(x interface{}, index int) (string, bool) {
+		tag, ok := x.(string)
+		return tag, ok
+	})
+	sort.Strings(infoTags)
+
+	return infoTags
+}
+
+// preservePackageTags applies the user-defined tags of a source package to
+// its copy, which the API doesn't copy, keeping immutable tags immutable.
+func preservePackageTags(pc *providerConfig, namespace, repository, identifier string, source *cloudsmith.Package) error {
+	immutable := packageInfoTags(source.GetTagsImmutable())
+	mutable, _ := lo.Difference(packageInfoTags(source.GetTags()), immutable)
+
+	for _, tags := range []struct {
+		tags        []string
+		isImmutable bool
+	}{
+		{mutable, false},
+		{immutable, true},
+	} {
+		if len(tags.tags) == 0 {
+			continue
+		}
+
+		data := cloudsmith.NewPackageTagRequest()
+		data.SetTags(tags.tags)
+		data.SetIsImmutable(tags.isImmutable)
+
+		req := pc.APIClient.PackagesApi.PackagesTag(pc.Auth, namespace, repository, identifier)
+		req = req.Data(*data)
+		if _, _, err := pc.APIClient.PackagesApi.PackagesTagExecute(req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// waitForPackageSync waits until a package has finished syncing, i.e. it has
+// been fully processed and indexed and can be downloaded, failing if the sync
+// fails.
+func waitForPackageSync(pc *providerConfig, namespace, repository, identifier string) error {
+	checkerFunc := func() error {
+		req := pc.APIClient.PackagesApi.PackagesStatus(pc.Auth, namespace, repository, identifier)
+		status, resp, err := pc.APIClient.PackagesApi.PackagesStatusExecute(req)
+		if err != nil {
+			if is404(resp) {
+				return errKeepWaiting
+			}
+			return err
+		}
+		if status.GetIsSyncFailed() {
+			if reason := status.GetStatusReason(); reason != "" {
+				return fmt.Errorf("package sync failed: %s", reason)
+			}
+			return errors.New("package sync failed")
+		}
+		if !status.GetIsSyncCompleted() {
+			return errKeepWaiting
+		}
+		return nil
+	}
+
+	return waiter(checkerFunc, packageCopySyncTimeout, packageCopySyncInterval)
+}
+
+func resourcePackageCopyRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	namespace := requiredString(d, "namespace")
+
+	destination, resp, err := repositoryAttributeSlugPerm(pc, d, namespace, "destination", DestinationSlugPerm)
+	if err != nil {
+		if is404(resp) {
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	req := pc.APIClient.PackagesApi.PackagesRead(pc.Auth, namespace, destination, requiredString(d, "package"))
+	copied, resp, err := pc.APIClient.PackagesApi.PackagesReadExecute(req)
+	if err != nil {
+		if is404(resp) {
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("cdn_url", copied.GetCdnUrl())
+	d.Set("version", copied.GetVersion())
+
+	return nil
+}
+
+// resourcePackageCopyUpdate only needs to handle wait_for_target_sync and
+// preserve_metadata, which are only used when copying the package, and
+// repository or destination being changed to another name of the same
+// repository; all other changes force a new copy.
+func resourcePackageCopyUpdate(d *schema.ResourceData, m interface{}) error {
+	return resourcePackageCopyRead(d, m)
+}
+
+// resourcePackageCopyDelete only removes the resource from state; copies are
+// left in the destination repository so that replacing the resource (e.g.
+// when promoting a new digest) doesn't remove previously promoted packages.
+func resourcePackageCopyDelete(d *schema.ResourceData, m interface{}) error {
+	return nil
+}
+
+// customizeDiffPackageCopy looks up the package currently matched by the
+// package query and, if its digest differs from the one last copied, plans
+// copying it again.
+func customizeDiffPackageCopy(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.NewValueKnown("package_query") {
+		return nil
+	}
+
+	pc := m.(*providerConfig)
+
+	// prefer the slug_perm, which is unaffected by renames, once known
+	repository := d.Get(RepositorySlugPerm).(string)
+	if repository == "" || d.HasChange("repository") {
+		repository = d.Get("repository").(string)
+	}
+
+	source, err := findPackageCopySource(pc, d.Get("namespace").(string), repository, d.Get("package_query").(string))
+	if err != nil {
+		// leave the existing copy alone rather than fail every plan while the
+		// source is missing or ambiguous; creating a new copy would fail anyway
+		return nil //nolint:nilerr
+	}
+
+	if source.GetChecksumSha256() == d.Get("source_digest").(string) {
+		return nil
+	}
+
+	if err := d.SetNew("source_digest", source.GetChecksumSha256()); err != nil {
+		return err
+	}
+	if err := d.SetNewComputed("source_package"); err != nil {
+		return err
+	}
+
+	return d.ForceNew("source_digest")
+}
+
+//nolint:funlen
+func resourcePackageCopy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePackageCopyCreate,
+		Read:   resourcePackageCopyRead,
+		Update: resourcePackageCopyUpdate,
+		Delete: resourcePackageCopyDelete,
+
+		CustomizeDiff: customdiff.All(
+			customizeDiffRepository("namespace"),
+			customizeDiffRepositoryAttribute("namespace", "destination", DestinationSlugPerm),
+			customizeDiffPackageCopy,
+		),
+
+		Schema: map[string]*schema.Schema{
+			"cdn_url": {
+				Type:        schema.TypeString,
+				Description: "The URL of the copied package.",
+				Computed:    true,
+			},
+			"destination": {
+				Type:         schema.TypeString,
+				Description:  "Repository to copy the package to.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			DestinationSlugPerm: {
+				Type:        schema.TypeString,
+				Description: "The slug_perm of the destination repository, used to identify it regardless of renames.",
+				Computed:    true,
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace to which the source and destination repositories belong.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"package": {
+				Type:        schema.TypeString,
+				Description: "The slug_perm of the copied package in the destination repository.",
+				Computed:    true,
+			},
+			"package_query": {
+				Type:             schema.TypeString,
+				Description:      "A package query matching exactly one package in the source repository to copy.",
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateRequiredPackageQuery,
+			},
+			"repository": {
+				Type:         schema.TypeString,
+				Description:  "Repository to copy the package from.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			RepositorySlugPerm: repositorySlugPermSchema(),
+			"preserve_metadata": {
+				Type: schema.TypeBool,
+				Description: "If true, the user-defined tags of the source package, which the API doesn't copy, " +
+					"are applied to the copy once it has synced, keeping immutable tags immutable.",
+				Optional: true,
+				Default:  false,
+			},
+			"republish": {
+				Type: schema.TypeBool,
+				Description: "If true, the package will overwrite any others with the same attributes " +
+					"(e.g. same version) in the destination repository.",
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+			"source_digest": {
+				Type: schema.TypeString,
+				Description: "The SHA-256 checksum of the source package when it was copied, which for " +
+					"container images is the manifest digest. The package is copied again when it changes.",
+				Computed: true,
+			},
+			"source_package": {
+				Type:        schema.TypeString,
+				Description: "The slug_perm of the source package which was copied.",
+				Computed:    true,
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Description: "The version of the copied package.",
+				Computed:    true,
+			},
+			"wait_for_target_sync": {
+				Type: schema.TypeBool,
+				Description: "If true, copying the package only succeeds once the copy has finished syncing " +
+					"(i.e. it is fully indexed and can be downloaded) in the destination repository.",
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
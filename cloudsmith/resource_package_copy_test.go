@@ -0,0 +1,101 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+var (
+	packageCopyTestNamespace   = os.Getenv("CLOUDSMITH_NAMESPACE")
+	packageCopyTestSource      = "terraform-acc-test-package-copy-src"
+	packageCopyTestDestination = "terraform-acc-test-package-copy-dst"
+)
+
+func TestPackageInfoTags(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		tags map[string]interface{}
+		want []string
+	}{
+		{"none", nil, []string{}},
+		{"generated only", map[string]interface{}{"version": []interface{}{"latest"}}, []string{}},
+		{"info", map[string]interface{}{
+			"info":    []interface{}{"stable", "release"},
+			"version": []interface{}{"latest"},
+		}, []string{"release", "stable"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := packageInfoTags(tc.tags); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("packageInfoTags() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestAccPackageCopy_basic copies a package between two repositories and
+// verifies the copy exists in the destination.
+func TestAccPackageCopy_basic(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPackageCopyConfigRepositories,
+				Check: resource.ComposeTestCheckFunc(
+					// packages can't be uploaded through the provider, so upload
+					// the package to copy once the source repository exists
+					func(s *terraform.State) error {
+						return uploadPackageTo(testAccProvider.Meta().(*providerConfig), packageCopyTestNamespace, packageCopyTestSource, false)
+					},
+				),
+			},
+			{
+				Config: testAccPackageCopyConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("cloudsmith_package_copy.test", "package"),
+					resource.TestCheckResourceAttrSet("cloudsmith_package_copy.test", "source_package"),
+					resource.TestCheckResourceAttrSet("cloudsmith_package_copy.test", "source_digest"),
+					resource.TestCheckResourceAttrSet("cloudsmith_package_copy.test", "cdn_url"),
+				),
+			},
+		},
+	})
+}
+
+var testAccPackageCopyConfigRepositories = fmt.Sprintf(`
+resource "cloudsmith_repository" "source" {
+	name      = "%s"
+	namespace = "%s"
+}
+
+resource "cloudsmith_repository" "destination" {
+	name      = "%s"
+	namespace = "%s"
+}
+`, packageCopyTestSource, packageCopyTestNamespace, packageCopyTestDestination, packageCopyTestNamespace)
+
+var testAccPackageCopyConfigBasic = testAccPackageCopyConfigRepositories + `
+resource "cloudsmith_package_copy" "test" {
+	namespace     = cloudsmith_repository.source.namespace
+	repository    = cloudsmith_repository.source.slug
+	destination   = cloudsmith_repository.destination.slug
+	package_query = "filename:hello.txt"
+
+	wait_for_target_sync = true
+}
+`
@@ -162,10 +162,10 @@ func packageDenyPolicy() *schema.Resource {
 				Default:     nil,
 			},
 			"package_query": {
-				Type:         schema.TypeString,
-				Description:  "The query to match the packages to be blocked.",
-				Required:     true,
-				ValidateFunc: validation.StringIsNotEmpty,
+				Type:             schema.TypeString,
+				Description:      "The query to match the packages to be blocked.",
+				Required:         true,
+				ValidateDiagFunc: validateRequiredPackageQuery,
 			},
 			"enabled": {
 				Type:        schema.TypeBool,
@@ -7,10 +7,21 @@ import (
 	"time"
 
 	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// repositoryTypeOpenSource is the repository type of open source
+// repositories, which are free to use but must belong to an open source
+// project.
+const repositoryTypeOpenSource = "Open-Source"
+
+// repositoryTypes are the repository types (visibility levels) supported by
+// the API.
+var repositoryTypes = []string{"Private", "Public", repositoryTypeOpenSource}
+
 func importRepository(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	idParts := strings.Split(d.Id(), ".")
 	if len(idParts) != 2 {
@@ -20,10 +31,51 @@ func importRepository(ctx context.Context, d *schema.ResourceData, m interface{}
 	}
 
 	d.Set("namespace", idParts[0])
+	d.Set("allow_region_migration", false)
 	d.SetId(idParts[1])
 	return []*schema.ResourceData{d}, nil
 }
 
+// resourceRepositoryCustomizeDiff refuses to plan a change of storage_region
+// unless allow_region_migration is set, as moving an existing repository to a
+// new region transfers all of its package files.
+func resourceRepositoryCustomizeDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("storage_region") || d.Get("allow_region_migration").(bool) {
+		return nil
+	}
+
+	oldRegion, newRegion := d.GetChange("storage_region")
+	return fmt.Errorf(
+		"changing storage_region from %q to %q transfers all package files in the repository to the new "+
+			"region; set allow_region_migration = true to confirm this change",
+		oldRegion, newRegion,
+	)
+}
+
+// resourceRepositoryCustomizeDiffOpenSource refuses to plan an open source
+// repository without the license and project URL the API requires to validate
+// that its project is open source.
+func resourceRepositoryCustomizeDiffOpenSource(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Get("repository_type").(string) != repositoryTypeOpenSource {
+		return nil
+	}
+
+	missing := []string{}
+	for _, key := range []string{"open_source_license", "open_source_project_url"} {
+		if d.NewValueKnown(key) && d.Get(key).(string) == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf(
+			"repository_type %q requires %s to be set, so that Cloudsmith can validate that the project is open source",
+			repositoryTypeOpenSource, strings.Join(missing, " and "),
+		)
+	}
+
+	return nil
+}
+
 func resourceRepositoryStorageRegionUpdate(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
@@ -58,6 +110,8 @@ func resourceRepositoryCreate(d *schema.ResourceData, m interface{}) error {
 		MoveOwn:                          optionalBool(d, "move_own"),
 		MovePackages:                     optionalString(d, "move_packages"),
 		Name:                             requiredString(d, "name"),
+		OpenSourceLicense:                nullableString(d, "open_source_license"),
+		OpenSourceProjectUrl:             nullableString(d, "open_source_project_url"),
 		ProxyNpmjs:                       optionalBool(d, "proxy_npmjs"),
 		ProxyPypi:                        optionalBool(d, "proxy_pypi"),
 		RawPackageIndexEnabled:           optionalBool(d, "raw_package_index_enabled"),
@@ -143,6 +197,8 @@ func resourceRepositoryRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("move_packages", repository.GetMovePackages())
 	d.Set("name", repository.GetName())
 	d.Set("namespace_url", repository.GetNamespaceUrl())
+	d.Set("open_source_license", repository.GetOpenSourceLicense())
+	d.Set("open_source_project_url", repository.GetOpenSourceProjectUrl())
 	d.Set("proxy_npmjs", repository.GetProxyNpmjs())
 	d.Set("proxy_pypi", repository.GetProxyPypi())
 	d.Set("raw_package_index_enabled", repository.GetRawPackageIndexEnabled())
@@ -187,6 +243,30 @@ func resourceRepositoryRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
+// resourceRepositoryUpdateContext wraps resourceRepositoryUpdate to warn when
+// the update has started migrating the repository to a new storage region.
+func resourceRepositoryUpdateContext(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	migrating := d.HasChange("storage_region")
+
+	if err := resourceRepositoryUpdate(d, m); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !migrating {
+		return nil
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Repository %s is being migrated to a new storage region", d.Id()),
+			Detail: "All package files in the repository are transferred to the new region in the background, " +
+				"which can take a long time for large repositories. Review any data residency requirements " +
+				"that applied to the previous region.",
+		},
+	}
+}
+
 func resourceRepositoryUpdate(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
@@ -213,6 +293,8 @@ func resourceRepositoryUpdate(d *schema.ResourceData, m interface{}) error {
 		MoveOwn:                          optionalBool(d, "move_own"),
 		MovePackages:                     optionalString(d, "move_packages"),
 		Name:                             optionalString(d, "name"),
+		OpenSourceLicense:                nullableString(d, "open_source_license"),
+		OpenSourceProjectUrl:             nullableString(d, "open_source_project_url"),
 		ProxyNpmjs:                       optionalBool(d, "proxy_npmjs"),
 		ProxyPypi:                        optionalBool(d, "proxy_pypi"),
 		RawPackageIndexEnabled:           optionalBool(d, "raw_package_index_enabled"),
@@ -298,16 +380,28 @@ func validateNoSpaces(val interface{}, key string) (warns []string, errs []error
 //nolint:funlen
 func resourceRepository() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceRepositoryCreate,
-		Read:   resourceRepositoryRead,
-		Update: resourceRepositoryUpdate,
-		Delete: resourceRepositoryDelete,
+		Create:        resourceRepositoryCreate,
+		Read:          resourceRepositoryRead,
+		UpdateContext: resourceRepositoryUpdateContext,
+		Delete:        resourceRepositoryDelete,
+
+		CustomizeDiff: customdiff.All(
+			resourceRepositoryCustomizeDiff,
+			resourceRepositoryCustomizeDiffOpenSource,
+		),
 
 		Importer: &schema.ResourceImporter{
 			StateContext: importRepository,
 		},
 
 		Schema: map[string]*schema.Schema{
+			"allow_region_migration": {
+				Type: schema.TypeBool,
+				Description: "If enabled, allows storage_region to be changed on an existing repository, which " +
+					"transfers all of its package files to the new region.",
+				Optional: true,
+				Default:  false,
+			},
 			"cdn_url": {
 				Type:        schema.TypeString,
 				Description: "Base URL from which packages and other artifacts are downloaded.",
@@ -451,6 +545,20 @@ func resourceRepository() *schema.Resource {
 				Description: "API endpoint where data about this namespace can be retrieved.",
 				Computed:    true,
 			},
+			"open_source_license": {
+				Type: schema.TypeString,
+				Description: "The SPDX identifier of the open source license of the repository's contents, " +
+					"shown on its page. Only applies to open source repositories.",
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"open_source_project_url": {
+				Type: schema.TypeString,
+				Description: "The URL of the open source project the repository's contents belong to, shown " +
+					"on its page. Only applies to open source repositories.",
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+			},
 			"proxy_npmjs": {
 				Type: schema.TypeBool,
 				Description: "If checked, Npm packages that are not in the repository when requested by clients will " +
@@ -503,11 +611,12 @@ func resourceRepository() *schema.Resource {
 				Type: schema.TypeString,
 				Description: "The repository type changes how it is accessed and billed. Private repositories " +
 					"can only be used on paid plans, but are visible only to you or authorised delegates. Public " +
-					"repositories are free to use on all plans and visible to all Cloudsmith users." +
-					"Valid values include: `Private` or `Public`.",
+					"repositories are free to use on all plans and visible to all Cloudsmith users. Open-Source " +
+					"repositories are public repositories of open source projects, which require open_source_license " +
+					"and open_source_project_url. Valid values include: `Private`, `Public` or `Open-Source`.",
 				Optional:     true,
 				Default:      "Private",
-				ValidateFunc: validation.StringInSlice([]string{"Private", "Public"}, false),
+				ValidateFunc: validation.StringInSlice(repositoryTypes, false),
 			},
 			"resync_own": {
 				Type: schema.TypeBool,
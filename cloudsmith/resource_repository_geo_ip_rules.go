@@ -2,12 +2,21 @@ package cloudsmith
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
 )
 
 const Namespace string = "namespace"
@@ -16,6 +25,165 @@ const CidrAllow string = "cidr_allow"
 const CidrDeny string = "cidr_deny"
 const CountryCodeAllow string = "country_code_allow"
 const CountryCodeDeny string = "country_code_deny"
+const WaitForConsistency string = "wait_for_consistency"
+const CidrAllowFile string = "cidr_allow_file"
+const CidrDenyFile string = "cidr_deny_file"
+const CidrAllowFileSha256 string = "cidr_allow_file_sha256"
+const CidrDenyFileSha256 string = "cidr_deny_file_sha256"
+const MaxRules string = "max_rules"
+
+// geoIpRulesFiles maps the attributes giving files of CIDRs to the lists they
+// replace and the attributes tracking their contents.
+var geoIpRulesFiles = map[string]struct{ List, Sha256 string }{
+	CidrAllowFile: {CidrAllow, CidrAllowFileSha256},
+	CidrDenyFile:  {CidrDeny, CidrDenyFileSha256},
+}
+
+// normalizeCidr returns the canonical form of a CIDR, in which any IPv6 hex
+// digits are lower case.
+func normalizeCidr(cidr string) string {
+	return strings.ToLower(strings.TrimSpace(cidr))
+}
+
+// normalizeCountryCode returns the canonical, upper case, form of an ISO
+// 3166-1 country code.
+func normalizeCountryCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// normalizeGeoIpRule adapts a normalization function for use with lo.Map.
+func normalizeGeoIpRule(normalize func(string) string) func(string, int) string {
+	return func(rule string, _ int) string {
+		return normalize(rule)
+	}
+}
+
+// geoIpRulesSetSchema returns the schema of a set of Geo/IP rules, whose
+// elements are normalized so that differences in order or case never
+// produce a diff.
+func geoIpRulesSetSchema(description string, normalize func(string) string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Description: description,
+		Optional:    true,
+		Set:         hashNormalizedString(normalize),
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringIsNotEmpty,
+			StateFunc: func(v interface{}) string {
+				return normalize(v.(string))
+			},
+		},
+	}
+}
+
+// readGeoIpRulesFile reads a newline-delimited file of CIDRs, in which
+// anything following a # is a comment, returning the normalized CIDRs
+// without duplicates.
+func readGeoIpRulesFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Geo/IP rules file: %w", err)
+	}
+
+	rules := []string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		if comment := strings.Index(line, "#"); comment >= 0 {
+			line = line[:comment]
+		}
+		if rule := normalizeCidr(line); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+
+	return lo.Uniq(rules), nil
+}
+
+// geoIpRulesSha256 returns a digest of a list of Geo/IP rules which doesn't
+// depend on their order or on duplicates.
+func geoIpRulesSha256(rules []string) string {
+	rules = lo.Uniq(rules)
+	sort.Strings(rules)
+
+	sum := sha256.Sum256([]byte(strings.Join(rules, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// expandGeoIpCidrs returns the CIDRs of a list, read from its file if one is
+// configured.
+func expandGeoIpCidrs(d *schema.ResourceData, key, fileKey string) ([]string, error) {
+	if path := d.Get(fileKey).(string); path != "" {
+		return readGeoIpRulesFile(path)
+	}
+
+	return lo.Map(expandStrings(d, key), normalizeGeoIpRule(normalizeCidr)), nil
+}
+
+// customizeDiffGeoIpRulesFiles plans an update whenever the rules in a file of
+// CIDRs differ from those last applied (or read back from the API), as
+// tracked by the digest of the list.
+func customizeDiffGeoIpRulesFiles(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	for fileKey, keys := range geoIpRulesFiles {
+		if !d.NewValueKnown(fileKey) {
+			if err := d.SetNewComputed(keys.Sha256); err != nil {
+				return err
+			}
+			continue
+		}
+
+		digest := ""
+		if path := d.Get(fileKey).(string); path != "" {
+			rules, err := readGeoIpRulesFile(path)
+			if err != nil {
+				return err
+			}
+			digest = geoIpRulesSha256(rules)
+		}
+
+		if digest != d.Get(keys.Sha256).(string) {
+			if err := d.SetNew(keys.Sha256, digest); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// customizeDiffGeoIpRulesMax rejects plans with more Geo/IP rules across all
+// four lists than max_rules, if set. Cloudsmith doesn't document a limit, so
+// this is up to the user, e.g. to catch lists generated from data sources
+// growing unexpectedly before any changes are made. Lists which aren't known
+// yet are checked when the plan is recomputed during apply, which is still
+// before the rules are sent.
+func customizeDiffGeoIpRulesMax(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	maxRules := d.Get(MaxRules).(int)
+	if maxRules == 0 {
+		return nil
+	}
+
+	total := 0
+	for _, key := range []string{CidrAllow, CidrDeny, CountryCodeAllow, CountryCodeDeny} {
+		total += d.Get(key).(*schema.Set).Len()
+	}
+	for fileKey := range geoIpRulesFiles {
+		if path := d.Get(fileKey).(string); path != "" {
+			// errors are reported by customizeDiffGeoIpRulesFiles
+			if rules, err := readGeoIpRulesFile(path); err == nil {
+				total += len(rules)
+			}
+		}
+	}
+
+	if total > maxRules {
+		return fmt.Errorf(
+			"too many Geo/IP rules: %d are configured across %s, %s, %s and %s, but %s is %d",
+			total, CidrAllow, CidrDeny, CountryCodeAllow, CountryCodeDeny, MaxRules, maxRules,
+		)
+	}
+
+	return nil
+}
 
 func importRepositoryGeoIpRules(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	idParts := strings.Split(d.Id(), ".")
@@ -27,22 +195,71 @@ func importRepositoryGeoIpRules(ctx context.Context, d *schema.ResourceData, m i
 
 	d.Set("namespace", idParts[0])
 	d.Set("repository", idParts[1])
+	d.Set(WaitForConsistency, true)
+	d.Set(EnsureFeatureEnabled, true)
+	d.Set(DisableFeatureOnDestroy, false)
+	d.Set(MaxRules, 0)
 	return []*schema.ResourceData{d}, nil
 }
 
+// enableGeoIpRules enables Geo/IP rules for a repository, retrying failures
+// which may be transient (those not already retried by the transport), every
+// interval until defaultUpdateTimeout. The request is rejected if they're
+// already enabled, which is tolerated so that creates can be retried, as are
+// other rejections (including conflicts, before they're retried) if the rules
+// turn out to be readable (i.e. enabled).
+func enableGeoIpRules(pc *providerConfig, namespace, repository string, interval time.Duration) error {
+	var enableErr error
+	checkerFunc := func() error {
+		resp, err := pc.GeoIP.Enable(namespace, repository)
+		if err == nil {
+			return nil
+		}
+
+		if resp != nil && resp.StatusCode < http.StatusInternalServerError {
+			if _, _, readErr := pc.GeoIP.Read(namespace, repository); readErr == nil {
+				return nil
+			}
+		}
+		if resp == nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusConflict {
+			enableErr = err
+			return errKeepWaiting
+		}
+		return err
+	}
+
+	err := checkerFunc()
+	if errors.Is(err, errKeepWaiting) {
+		err = waiter(checkerFunc, defaultUpdateTimeout, interval)
+	}
+	if errors.Is(err, errTimedOut) {
+		return fmt.Errorf("error enabling Geo/IP rules: %w", enableErr)
+	}
+
+	return err
+}
+
 func resourceRepositoryGeoIpRulesCreate(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, Namespace)
-	repository := requiredString(d, Repository)
-
-	// Ensure that Geo/IP rules are enabled for the Repository
-	req := pc.APIClient.ReposApi.ReposGeoipEnable(pc.Auth, namespace, repository)
-	_, err := pc.APIClient.ReposApi.ReposGeoipEnableExecute(req)
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
 	if err != nil {
 		return err
 	}
 
+	// Ensure that Geo/IP rules are enabled for the Repository
+	if requiredBool(d, EnsureFeatureEnabled) {
+		if err := enableGeoIpRules(pc, namespace, repository, defaultUpdateInterval); err != nil {
+			return err
+		}
+	}
+
+	// Geo/IP is now enabled, so the resource is tracked even if updating the
+	// rules fails, which makes Terraform replace it (disabling Geo/IP again)
+	// on the next apply rather than leaving it enabled outside of state.
+	d.SetId(fmt.Sprintf("%s.%s", namespace, requiredString(d, Repository)))
+
 	// The actual "create" is just the same as "update" for this resource.
 	return resourceRepositoryGeoIpRulesUpdate(d, m)
 }
@@ -51,11 +268,17 @@ func resourceRepositoryGeoIpRulesRead(d *schema.ResourceData, m interface{}) err
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, Namespace)
-	repository := requiredString(d, Repository)
+	repository, resp, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		if is404(resp) {
+			d.SetId("")
+			return nil
+		}
 
-	req := pc.APIClient.ReposApi.ReposGeoipRead(pc.Auth, namespace, repository)
+		return err
+	}
 
-	geoIpRules, resp, err := pc.APIClient.ReposApi.ReposGeoipReadExecute(req)
+	geoIpRules, resp, err := pc.GeoIP.Read(namespace, repository)
 	if err != nil {
 		if is404(resp) {
 			d.SetId("")
@@ -68,52 +291,85 @@ func resourceRepositoryGeoIpRulesRead(d *schema.ResourceData, m interface{}) err
 	cidr := geoIpRules.GetCidr()
 	countryCode := geoIpRules.GetCountryCode()
 
-	_ = d.Set(CidrAllow, flattenStrings(cidr.GetAllow()))
-	_ = d.Set(CidrDeny, flattenStrings(cidr.GetDeny()))
-	_ = d.Set(CountryCodeAllow, flattenStrings(countryCode.GetAllow()))
-	_ = d.Set(CountryCodeDeny, flattenStrings(countryCode.GetDeny()))
+	setGeoIpCidrs(d, CidrAllow, CidrAllowFile, lo.Map(cidr.GetAllow(), normalizeGeoIpRule(normalizeCidr)))
+	setGeoIpCidrs(d, CidrDeny, CidrDenyFile, lo.Map(cidr.GetDeny(), normalizeGeoIpRule(normalizeCidr)))
+	_ = d.Set(CountryCodeAllow, flattenStringSet(
+		lo.Map(countryCode.GetAllow(), normalizeGeoIpRule(normalizeCountryCode)), hashNormalizedString(normalizeCountryCode),
+	))
+	_ = d.Set(CountryCodeDeny, flattenStringSet(
+		lo.Map(countryCode.GetDeny(), normalizeGeoIpRule(normalizeCountryCode)), hashNormalizedString(normalizeCountryCode),
+	))
 
 	// namespace and repository are not returned from the read
-	// endpoint, so we can use the values stored in resource state. We rely on
-	// ForceNew to ensure if either changes a new resource is created.
+	// endpoint, so we can use the values stored in resource state. Changing
+	// namespace forces a new resource; requests are made with
+	// repository_slug_perm, so repository is kept as configured and only
+	// forces a new resource if it refers to another repository (see
+	// customizeDiffRepository).
 	_ = d.Set(Namespace, namespace)
-	_ = d.Set(Repository, repository)
+	_ = d.Set(Repository, requiredString(d, Repository))
 
 	return nil
 }
 
+// setGeoIpCidrs stores a list of CIDRs read from the API in state. When the
+// list is configured with a file only its digest is stored, so that the list
+// itself doesn't bloat the state.
+func setGeoIpCidrs(d *schema.ResourceData, key, fileKey string, cidrs []string) {
+	if d.Get(fileKey).(string) == "" {
+		_ = d.Set(key, flattenStringSet(cidrs, hashNormalizedString(normalizeCidr)))
+		_ = d.Set(geoIpRulesFiles[fileKey].Sha256, "")
+		return
+	}
+
+	_ = d.Set(geoIpRulesFiles[fileKey].Sha256, geoIpRulesSha256(cidrs))
+}
+
 func resourceRepositoryGeoIpRulesUpdate(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, Namespace)
-	repository := requiredString(d, Repository)
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
+
+	cidrAllow, err := expandGeoIpCidrs(d, CidrAllow, CidrAllowFile)
+	if err != nil {
+		return err
+	}
+	cidrDeny, err := expandGeoIpCidrs(d, CidrDeny, CidrDenyFile)
+	if err != nil {
+		return err
+	}
 
 	updateData := cloudsmith.RepositoryGeoIpRulesRequest{
 		CountryCode: cloudsmith.RepositoryGeoIpCountryCode{
-			Allow: expandStrings(d, CountryCodeAllow),
-			Deny:  expandStrings(d, CountryCodeDeny),
+			Allow: lo.Map(expandStrings(d, CountryCodeAllow), normalizeGeoIpRule(normalizeCountryCode)),
+			Deny:  lo.Map(expandStrings(d, CountryCodeDeny), normalizeGeoIpRule(normalizeCountryCode)),
 		},
 		Cidr: cloudsmith.RepositoryGeoIpCidr{
-			Allow: expandStrings(d, CidrAllow),
-			Deny:  expandStrings(d, CidrDeny),
+			Allow: cidrAllow,
+			Deny:  cidrDeny,
 		},
 	}
 
-	updateRequest := pc.APIClient.ReposApi.ReposGeoipUpdate(pc.Auth, namespace, repository)
-	updateRequest = updateRequest.Data(updateData)
-
-	_, _, updateErr := pc.APIClient.ReposApi.ReposGeoipUpdateExecute(updateRequest)
+	_, updateErr := pc.GeoIP.Update(namespace, repository, updateData)
 	if updateErr != nil {
 		return updateErr
 	}
 
-	d.SetId(fmt.Sprintf("%s.%s", namespace, repository))
+	d.SetId(fmt.Sprintf("%s.%s", namespace, requiredString(d, Repository)))
+
+	// The rules are applied asynchronously, so unless disabled, poll until
+	// the read endpoint reflects what we sent.
+	if !requiredBool(d, WaitForConsistency) {
+		return resourceRepositoryGeoIpRulesReadAfterUpdate(d, m, updateData)
+	}
 
-	// Workaround for replication lag
 	checkerFunc := func() error {
 		// Call the read endpoint
-		readRequest := pc.APIClient.ReposApi.ReposGeoipRead(pc.Auth, namespace, repository)
-		readData, _, readErr := pc.APIClient.ReposApi.ReposGeoipReadExecute(readRequest)
+		readData, _, readErr := pc.GeoIP.Read(namespace, repository)
 		if readErr != nil {
 			return readErr
 		}
@@ -147,18 +403,40 @@ func resourceRepositoryGeoIpRulesUpdate(d *schema.ResourceData, m interface{}) e
 		return waitErr
 	}
 
-	return resourceRepositoryGeoIpRulesRead(d, m)
+	return resourceRepositoryGeoIpRulesReadAfterUpdate(d, m, updateData)
+}
+
+// resourceRepositoryGeoIpRulesReadAfterUpdate reads the rules after they have
+// been updated, keeping the digests of files of CIDRs as planned even if the
+// rules haven't been rolled out yet.
+func resourceRepositoryGeoIpRulesReadAfterUpdate(
+	d *schema.ResourceData, m interface{}, updateData cloudsmith.RepositoryGeoIpRulesRequest,
+) error {
+	if err := resourceRepositoryGeoIpRulesRead(d, m); err != nil {
+		return err
+	}
+
+	cidr := updateData.GetCidr()
+	for fileKey, cidrs := range map[string][]string{CidrAllowFile: cidr.GetAllow(), CidrDenyFile: cidr.GetDeny()} {
+		if d.Get(fileKey).(string) != "" {
+			_ = d.Set(geoIpRulesFiles[fileKey].Sha256, geoIpRulesSha256(cidrs))
+		}
+	}
+
+	return nil
 }
 
 func resourceRepositoryGeoIpRulesDelete(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, "namespace")
-	repository := requiredString(d, "repository")
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
 
 	// There isn't a DELETE endpoint, so just update the rules to be empty.
-	req := pc.APIClient.ReposApi.ReposGeoipUpdate(pc.Auth, namespace, repository)
-	req = req.Data(cloudsmith.RepositoryGeoIpRulesRequest{
+	_, err = pc.GeoIP.Update(namespace, repository, cloudsmith.RepositoryGeoIpRulesRequest{
 		CountryCode: cloudsmith.RepositoryGeoIpCountryCode{
 			Allow: []string{},
 			Deny:  []string{},
@@ -168,11 +446,16 @@ func resourceRepositoryGeoIpRulesDelete(d *schema.ResourceData, m interface{}) e
 			Deny:  []string{},
 		},
 	})
-	_, _, err := pc.APIClient.ReposApi.ReposGeoipUpdateExecute(req)
 	if err != nil {
 		return err
 	}
 
+	if requiredBool(d, DisableFeatureOnDestroy) {
+		if resp, err := pc.GeoIP.Disable(namespace, repository); err != nil && !is404(resp) {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -180,50 +463,70 @@ func resourceRepositoryGeoIpRulesDelete(d *schema.ResourceData, m interface{}) e
 func resourceRepositoryGeoIpRules() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceRepositoryGeoIpRulesCreate,
-		Read:   resourceRepositoryGeoIpRulesRead,
+		ReadContext: readWithDriftDetection(
+			resourceRepositoryGeoIpRulesRead, CidrAllow, CidrDeny, CountryCodeAllow, CountryCodeDeny, CidrAllowFileSha256, CidrDenyFileSha256,
+		),
 		Update: resourceRepositoryGeoIpRulesUpdate,
 		Delete: resourceRepositoryGeoIpRulesDelete,
 
+		CustomizeDiff: customdiff.All(
+			customizeDiffRepository(Namespace),
+			customizeDiffGeoIpRulesFiles,
+			customizeDiffGeoIpRulesMax,
+		),
+
 		Importer: &schema.ResourceImporter{
 			StateContext: importRepositoryGeoIpRules,
 		},
 
 		Schema: map[string]*schema.Schema{
-			CidrAllow: {
-				Type:        schema.TypeSet,
-				Description: "The list of IP Addresses for which to allow access, expressed in CIDR notation.",
-				Optional:    true,
-				Elem: &schema.Schema{
-					Type:         schema.TypeString,
-					ValidateFunc: validation.StringIsNotEmpty,
-				},
+			CidrAllow: geoIpRulesSetSchema(
+				"The list of IP Addresses for which to allow access, expressed in CIDR notation.", normalizeCidr,
+			),
+			CidrDeny: geoIpRulesSetSchema(
+				"The list of IP Addresses for which to deny access, expressed in CIDR notation.", normalizeCidr,
+			),
+			CidrAllowFile: {
+				Type: schema.TypeString,
+				Description: "Path to a file of IP Addresses for which to allow access, expressed in CIDR notation, " +
+					"one per line. Anything following a # on a line is a comment.",
+				Optional:      true,
+				ConflictsWith: []string{CidrAllow},
+				ValidateFunc:  validation.StringIsNotEmpty,
 			},
-			CidrDeny: {
-				Type:        schema.TypeSet,
-				Description: "The list of IP Addresses for which to deny access, expressed in CIDR notation.",
-				Optional:    true,
-				Elem: &schema.Schema{
-					Type:         schema.TypeString,
-					ValidateFunc: validation.StringIsNotEmpty,
-				},
+			CidrAllowFileSha256: {
+				Type:        schema.TypeString,
+				Description: "The SHA-256 digest of the CIDRs in cidr_allow_file, used to detect changes.",
+				Computed:    true,
 			},
-			CountryCodeAllow: {
-				Type:        schema.TypeSet,
-				Description: "The list of countries for which to allow access, expressed in ISO 3166-1 country codes.",
-				Optional:    true,
-				Elem: &schema.Schema{
-					Type:         schema.TypeString,
-					ValidateFunc: validation.StringIsNotEmpty,
-				},
+			CidrDenyFile: {
+				Type: schema.TypeString,
+				Description: "Path to a file of IP Addresses for which to deny access, expressed in CIDR notation, " +
+					"one per line. Anything following a # on a line is a comment.",
+				Optional:      true,
+				ConflictsWith: []string{CidrDeny},
+				ValidateFunc:  validation.StringIsNotEmpty,
 			},
-			CountryCodeDeny: {
-				Type:        schema.TypeSet,
-				Description: "The list of countries for which to deny access, expressed in ISO 3166-1 country codes.",
-				Optional:    true,
-				Elem: &schema.Schema{
-					Type:         schema.TypeString,
-					ValidateFunc: validation.StringIsNotEmpty,
-				},
+			CidrDenyFileSha256: {
+				Type:        schema.TypeString,
+				Description: "The SHA-256 digest of the CIDRs in cidr_deny_file, used to detect changes.",
+				Computed:    true,
+			},
+			CountryCodeAllow: geoIpRulesSetSchema(
+				"The list of countries for which to allow access, expressed in ISO 3166-1 country codes.", normalizeCountryCode,
+			),
+			CountryCodeDeny: geoIpRulesSetSchema(
+				"The list of countries for which to deny access, expressed in ISO 3166-1 country codes.", normalizeCountryCode,
+			),
+			EnsureFeatureEnabled:    ensureFeatureEnabledSchema("Geo/IP for the repository", true),
+			DisableFeatureOnDestroy: disableFeatureOnDestroySchema("Geo/IP for the repository"),
+			MaxRules: {
+				Type: schema.TypeInt,
+				Description: "The maximum number of rules across all four lists, checked at plan time so that " +
+					"oversized lists fail before any changes are made. Zero means no limit.",
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
 			},
 			Namespace: {
 				Type:         schema.TypeString,
@@ -232,13 +535,20 @@ func resourceRepositoryGeoIpRules() *schema.Resource {
 				ForceNew:     true,
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
+			WaitForConsistency: {
+				Type: schema.TypeBool,
+				Description: "If enabled, wait after applying the rules until they are returned by the API, as the " +
+					"rules are rolled out asynchronously.",
+				Optional: true,
+				Default:  true,
+			},
 			Repository: {
 				Type:         schema.TypeString,
-				Description:  "Repository to which these Geo/IP rules belong.",
+				Description:  "Repository to which these Geo/IP rules belong, identified by its slug or slug_perm.",
 				Required:     true,
-				ForceNew:     true,
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
+			RepositorySlugPerm: repositorySlugPermSchema(),
 		},
 	}
 }
@@ -2,11 +2,16 @@
 package cloudsmith
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
+	"github.com/cloudsmith-io/cloudsmith-api-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -51,6 +56,147 @@ var testAccRepositoryGeoIpRulesConfigCreate = fmt.Sprintf(configTemplateWithRule
 var testAccRepositoryGeoIpRulesConfigUpdate = fmt.Sprintf(configTemplateWithRules, namespace, UpdatedCidrAllow, UpdatedCidrDeny, UpdatedCountryCodeAllow, UpdatedCountryCodeDeny)
 var testAccRepositoryGeoIpRulesConfigDefault = fmt.Sprintf(configTemplateWithoutRules, namespace)
 
+// TestRepositoryGeoIpRulesNormalization verifies that rules which only differ
+// in order or case are treated as the same set.
+func TestRepositoryGeoIpRulesNormalization(t *testing.T) {
+	t.Parallel()
+
+	resourceSchema := resourceRepositoryGeoIpRules().Schema
+
+	d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{
+		CidrAllow:        []interface{}{"2001:DB8::/32", "10.0.0.0/8"},
+		CountryCodeAllow: []interface{}{"gb", "Us"},
+	})
+
+	if !d.Get(CidrAllow).(*schema.Set).HashEqual(schema.NewSet(resourceSchema[CidrAllow].Set, []interface{}{"10.0.0.0/8", "2001:db8::/32"})) {
+		t.Errorf("expected CIDRs to be normalized, got: %v", d.Get(CidrAllow))
+	}
+	if !d.Get(CountryCodeAllow).(*schema.Set).HashEqual(schema.NewSet(resourceSchema[CountryCodeAllow].Set, []interface{}{"US", "GB"})) {
+		t.Errorf("expected country codes to be normalized, got: %v", d.Get(CountryCodeAllow))
+	}
+}
+
+// TestRepositoryGeoIpRulesMaxRules verifies that plans with more rules in total
+// than max_rules are rejected, and that there is no limit by default.
+func TestRepositoryGeoIpRulesMaxRules(t *testing.T) {
+	t.Parallel()
+
+	cidrs := func(count int, prefix string) []interface{} {
+		out := make([]interface{}, count)
+		for i := range out {
+			out[i] = fmt.Sprintf("%s.%d.%d.0/24", prefix, i/256, i%256)
+		}
+		return out
+	}
+
+	testCases := []struct {
+		name     string
+		maxRules int
+		allow    int
+		deny     int
+		wantErr  bool
+	}{
+		{"no limit", 0, 1500, 1500, false},
+		{"within limit", 100, 50, 50, false},
+		{"over limit", 100, 50, 51, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				Namespace:  "namespace",
+				Repository: "repository",
+				MaxRules:   tc.maxRules,
+				CidrAllow:  cidrs(tc.allow, "10"),
+				CidrDeny:   cidrs(tc.deny, "11"),
+			})
+
+			_, err := resourceRepositoryGeoIpRules().Diff(context.Background(), nil, config, &providerConfig{})
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("expected error: %t, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestRepositoryGeoIpRulesFile verifies that files of CIDRs are parsed, that
+// their digest only depends on the rules they contain, and that changing the
+// rules in a file plans an update.
+func TestRepositoryGeoIpRulesFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeRules := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	path := writeRules("allow.txt", "# office\n10.0.0.0/24\n\n  2001:DB8::/32  # VPN\n10.0.0.0/24\n")
+	reordered := writeRules("reordered.txt", "2001:db8::/32\n10.0.0.0/24\n10.0.0.0/24\n")
+	changed := writeRules("changed.txt", "10.0.0.0/24\n")
+
+	rules, err := readGeoIpRulesFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := []string{"10.0.0.0/24", "2001:db8::/32"}; !reflect.DeepEqual(rules, expected) {
+		t.Fatalf("expected %v, got %v", expected, rules)
+	}
+
+	digest := geoIpRulesSha256(rules)
+
+	state := &terraform.InstanceState{
+		ID: "namespace.repository",
+		Attributes: map[string]string{
+			Namespace:            "namespace",
+			Repository:           "repository",
+			CidrAllowFile:        path,
+			CidrAllowFileSha256:  digest,
+			EnsureFeatureEnabled: "true",
+			WaitForConsistency:   "true",
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		path       string
+		wantChange bool
+	}{
+		{"unchanged", path, false},
+		{"reordered with duplicates", reordered, false},
+		{"changed", changed, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				Namespace:     "namespace",
+				Repository:    "repository",
+				CidrAllowFile: tc.path,
+			})
+
+			diff, err := resourceRepositoryGeoIpRules().Diff(context.Background(), state, config, &providerConfig{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			changed := diff != nil && diff.Attributes[CidrAllowFileSha256] != nil
+			if changed != tc.wantChange {
+				t.Fatalf("expected change to %s: %t, got diff: %v", CidrAllowFileSha256, tc.wantChange, diff)
+			}
+		})
+	}
+}
+
 // TestAccRepositoryGeoIpRules_basic spins up a repository with all default options,
 // creates a set of geo/ip rules for the repository and verifies they exist. Then it
 // changes the geo/ip rules and verifies they've been set correctly before tearing down the
@@ -190,3 +336,35 @@ func testAccRepositoryGeoIpRulesCheckExists(resourceName string, expectedCidrAll
 		return nil
 	}
 }
+
+// BenchmarkRepositoryGeoIpRulesRead reads 10,000 CIDRs into state, as for
+// lists generated from data sources.
+func BenchmarkRepositoryGeoIpRulesRead(b *testing.B) {
+	geoIP := newFakeGeoIPService()
+	geoIP.rules["my-org/AbCdEfGhIjKl"] = &cloudsmith.RepositoryGeoIpRules{
+		Cidr: cloudsmith.RepositoryGeoIpCidr{Allow: benchmarkCidrs(10000), Deny: []string{}},
+	}
+	pc := &providerConfig{
+		GeoIP: geoIP,
+		Repos: &fakeReposService{repositories: map[string][]cloudsmith.Repository{
+			"my-org": {{Slug: cloudsmith.PtrString("my-repo"), SlugPerm: cloudsmith.PtrString("AbCdEfGhIjKl")}},
+		}},
+	}
+
+	r := resourceRepositoryGeoIpRules()
+	state := &terraform.InstanceState{
+		ID: "my-org.my-repo",
+		Attributes: map[string]string{
+			Namespace:          "my-org",
+			Repository:         "my-repo",
+			RepositorySlugPerm: "AbCdEfGhIjKl",
+		},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := resourceRepositoryGeoIpRulesRead(r.Data(state), pc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -3,10 +3,13 @@ package cloudsmith
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/samber/lo"
@@ -135,31 +138,81 @@ func importRepositoryPrivileges(ctx context.Context, d *schema.ResourceData, m i
 
 	d.Set("organization", idParts[0])
 	d.Set("repository", idParts[1])
+	d.Set(MaxRemovalsPerApply, 0)
 	return []*schema.ResourceData{d}, nil
 }
 
-func resourceRepositoryPrivilegesCreateUpdate(d *schema.ResourceData, m interface{}) error {
+// removedRepositoryPrivileges returns the privileges in state of services,
+// teams and users which are no longer configured at all, sorted by type and
+// slug. Changing the level of a privilege doesn't count as removing it.
+func removedRepositoryPrivileges(d *schema.ResourceData) []cloudsmith.RepositoryPrivilegeDict {
+	removed := []cloudsmith.RepositoryPrivilegeDict{}
+
+	for _, principalType := range []string{principalTypeService, principalTypeTeam, principalTypeUser} {
+		oldSet, newSet := d.GetChange(principalType)
+
+		slugs := lo.Map(newSet.(*schema.Set).List(), func(x interface{}, index int) string {
+			return x.(map[string]interface{})["slug"].(string)
+		})
+
+		oldPrivileges := lo.Map(oldSet.(*schema.Set).List(), func(x interface{}, index int) map[string]interface{} {
+			return x.(map[string]interface{})
+		})
+		sort.Slice(oldPrivileges, func(i, j int) bool {
+			return oldPrivileges[i]["slug"].(string) < oldPrivileges[j]["slug"].(string)
+		})
+
+		for _, old := range oldPrivileges {
+			if lo.Contains(slugs, old["slug"].(string)) {
+				continue
+			}
+
+			p := cloudsmith.RepositoryPrivilegeDict{}
+			p.SetPrivilege(old["privilege"].(string))
+			switch principalType {
+			case principalTypeService:
+				p.SetService(old["slug"].(string))
+			case principalTypeTeam:
+				p.SetTeam(old["slug"].(string))
+			default:
+				p.SetUser(old["slug"].(string))
+			}
+			removed = append(removed, p)
+		}
+	}
+
+	return removed
+}
+
+func resourceRepositoryPrivilegesCreateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	pc := m.(*providerConfig)
 
 	organization := requiredString(d, "organization")
-	repository := requiredString(d, "repository")
+	repository, _, err := repositorySlugPerm(pc, d, organization)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	privileges := []cloudsmith.RepositoryPrivilegeDict{}
 	privileges = append(privileges, expandRepositoryPrivilegeServices(d)...)
 	privileges = append(privileges, expandRepositoryPrivilegeTeams(d)...)
 	privileges = append(privileges, expandRepositoryPrivilegeUsers(d)...)
 
+	// privileges whose removal is deferred are kept by sending them again
+	_, deferred := deferRemovals(d, removedRepositoryPrivileges(d))
+	privileges = append(privileges, deferred...)
+
 	req := pc.APIClient.ReposApi.ReposPrivilegesUpdate(pc.Auth, organization, repository)
 	req = req.Data(cloudsmith.RepositoryPrivilegeInputRequest{
 		Privileges: privileges,
 	})
 
-	_, err := pc.APIClient.ReposApi.ReposPrivilegesUpdateExecute(req)
+	_, err = pc.APIClient.ReposApi.ReposPrivilegesUpdateExecute(req)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	d.SetId(fmt.Sprintf("%s.%s", organization, repository))
+	d.SetId(fmt.Sprintf("%s.%s", organization, requiredString(d, "repository")))
 
 	checkerFunc := func() error {
 		// this is somewhat of a hack until we have a better way to poll for
@@ -168,53 +221,70 @@ func resourceRepositoryPrivilegesCreateUpdate(d *schema.ResourceData, m interfac
 		return nil
 	}
 	if err := waiter(checkerFunc, defaultUpdateTimeout, defaultUpdateInterval); err != nil {
-		return fmt.Errorf("error waiting for privileges (%s) to be updated: %w", d.Id(), err)
+		return diag.Errorf("error waiting for privileges (%s) to be updated: %s", d.Id(), err)
 	}
 
-	return resourceRepositoryPrivilegesRead(d, m)
+	diags := deferredRemovalsWarning(d, "repository privileges", len(deferred))
+	return append(diags, diag.FromErr(resourceRepositoryPrivilegesRead(d, m))...)
 }
 
-func resourceRepositoryPrivilegesRead(d *schema.ResourceData, m interface{}) error {
-	pc := m.(*providerConfig)
-
-	organization := requiredString(d, "organization")
-	repository := requiredString(d, "repository")
-
+// retrieveRepositoryPrivileges returns every privilege of a repository. The
+// privileges endpoint doesn't report how many pages there are, so pages are
+// requested until one comes back short.
+func retrieveRepositoryPrivileges(
+	pc *providerConfig, organization, repository string,
+) ([]cloudsmith.RepositoryPrivilegeDict, *http.Response, error) {
 	var allPrivileges []cloudsmith.RepositoryPrivilegeDict
-	page := int64(1)
 	pageSize := int64(1000)
 
-	for {
-		req := pc.APIClient.ReposApi.ReposPrivilegesList(pc.Auth, organization, repository)
-		req = req.Page(page)
-		req = req.PageSize(pageSize)
-		privileges, resp, err := pc.APIClient.ReposApi.ReposPrivilegesListExecute(req)
+	for page := int64(1); ; page++ {
+		privileges, resp, err := pc.Repos.ListPrivileges(organization, repository, page, pageSize)
 		if err != nil {
-			if is404(resp) {
-				d.SetId("")
-				return nil
-			}
-			return err
+			return nil, resp, err
 		}
 
-		allPrivileges = append(allPrivileges, privileges.GetPrivileges()...)
+		allPrivileges = append(allPrivileges, privileges...)
+
+		if int64(len(privileges)) < pageSize {
+			return allPrivileges, resp, nil
+		}
+	}
+}
+
+func resourceRepositoryPrivilegesRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	organization := requiredString(d, "organization")
+	repository, resp, err := repositorySlugPerm(pc, d, organization)
+	if err != nil {
+		if is404(resp) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
 
-		// Check if we have retrieved all pages
-		if int64(len(privileges.GetPrivileges())) < pageSize {
-			break
+	allPrivileges, resp, err := retrieveRepositoryPrivileges(pc, organization, repository)
+	if err != nil {
+		if is404(resp) {
+			d.SetId("")
+			return nil
 		}
-		page++
+		return err
 	}
 
 	d.Set("service", flattenRepositoryPrivilegeServices(allPrivileges))
 	d.Set("team", flattenRepositoryPrivilegeTeams(allPrivileges))
 	d.Set("user", flattenRepositoryPrivilegeUsers(allPrivileges))
 
-	// namespace and repository are not returned from the privileges read
-	// endpoint, so we can use the values stored in resource state. We rely on
-	// ForceNew to ensure if either changes a new resource is created.
+	// organization and repository are not returned from the privileges read
+	// endpoint, so we can use the values stored in resource state. Changing
+	// organization forces a new resource; requests are made with
+	// repository_slug_perm, so repository is kept as configured and only
+	// forces a new resource if it refers to another repository (see
+	// customizeDiffRepository).
 	d.Set("organization", organization)
-	d.Set("repository", repository)
+	d.Set("repository", requiredString(d, "repository"))
 
 	return nil
 }
@@ -223,14 +293,17 @@ func resourceRepositoryPrivilegesDelete(d *schema.ResourceData, m interface{}) e
 	pc := m.(*providerConfig)
 
 	organization := requiredString(d, "organization")
-	repository := requiredString(d, "repository")
+	repository, _, err := repositorySlugPerm(pc, d, organization)
+	if err != nil {
+		return err
+	}
 
 	req := pc.APIClient.ReposApi.ReposPrivilegesUpdate(pc.Auth, organization, repository)
 	req = req.Data(cloudsmith.RepositoryPrivilegeInputRequest{
 		Privileges: []cloudsmith.RepositoryPrivilegeDict{},
 	})
 
-	_, err := pc.APIClient.ReposApi.ReposPrivilegesUpdateExecute(req)
+	_, err = pc.APIClient.ReposApi.ReposPrivilegesUpdateExecute(req)
 	if err != nil {
 		return err
 	}
@@ -251,10 +324,12 @@ func resourceRepositoryPrivilegesDelete(d *schema.ResourceData, m interface{}) e
 //nolint:funlen
 func resourceRepositoryPrivileges() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceRepositoryPrivilegesCreateUpdate,
-		Read:   resourceRepositoryPrivilegesRead,
-		Update: resourceRepositoryPrivilegesCreateUpdate,
-		Delete: resourceRepositoryPrivilegesDelete,
+		CreateContext: resourceRepositoryPrivilegesCreateUpdate,
+		Read:          resourceRepositoryPrivilegesRead,
+		UpdateContext: resourceRepositoryPrivilegesCreateUpdate,
+		Delete:        resourceRepositoryPrivilegesDelete,
+
+		CustomizeDiff: customizeDiffRepository("organization"),
 
 		Importer: &schema.ResourceImporter{
 			StateContext: importRepositoryPrivileges,
@@ -270,11 +345,12 @@ func resourceRepositoryPrivileges() *schema.Resource {
 			},
 			"repository": {
 				Type:         schema.TypeString,
-				Description:  "Repository to which these privileges belong.",
+				Description:  "Repository to which these privileges belong, identified by its slug or slug_perm.",
 				Required:     true,
-				ForceNew:     true,
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
+			MaxRemovalsPerApply: maxRemovalsPerApplySchema("privileges"),
+			RepositorySlugPerm:  repositorySlugPermSchema(),
 			"service": {
 				Type: schema.TypeSet,
 				Elem: &schema.Resource{
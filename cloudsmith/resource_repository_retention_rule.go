@@ -24,7 +24,10 @@ func resourceRepoRetentionRuleUpdate(d *schema.ResourceData, meta interface{}) e
 	pc := meta.(*providerConfig)
 
 	namespace := requiredString(d, "namespace")
-	repo := requiredString(d, "repository")
+	repo, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
 
 	// Check if the operation is a delete operation
 	isDelete := !d.Get("retention_enabled").(bool)
@@ -76,7 +79,7 @@ func resourceRepoRetentionRuleUpdate(d *schema.ResourceData, meta interface{}) e
 	}
 
 	// Handle the response
-	d.SetId(fmt.Sprintf("%s.%s", namespace, repo))
+	d.SetId(fmt.Sprintf("%s.%s", namespace, requiredString(d, "repository")))
 	return resourceRepoRetentionRuleRead(d, meta)
 }
 
@@ -84,7 +87,13 @@ func resourceRepoRetentionRuleRead(d *schema.ResourceData, meta interface{}) err
 	pc := meta.(*providerConfig)
 
 	namespace := requiredString(d, "namespace")
-	repo := requiredString(d, "repository")
+	repo, httpResp, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		if is404(httpResp) {
+			return fmt.Errorf("namespace or repository not found: %s", err)
+		}
+		return err
+	}
 
 	// Execute the request
 	resp, httpResp, err := pc.APIClient.ReposApi.RepoRetentionRead(pc.Auth, namespace, repo).Execute()
@@ -111,7 +120,7 @@ func resourceRepoRetentionRuleRead(d *schema.ResourceData, meta interface{}) err
 	d.Set("retention_size_limit", resp.RetentionSizeLimit)
 
 	d.Set("namespace", namespace)
-	d.SetId(fmt.Sprintf("%s.%s", namespace, repo))
+	d.SetId(fmt.Sprintf("%s.%s", namespace, requiredString(d, "repository")))
 
 	return nil
 }
@@ -122,6 +131,9 @@ func resourceRepoRetentionRule() *schema.Resource {
 		Read:   resourceRepoRetentionRuleRead,
 		Update: resourceRepoRetentionRuleUpdate,
 		Delete: resourceRepoRetentionRuleUpdate,
+
+		CustomizeDiff: customizeDiffRepository("namespace"),
+
 		Importer: &schema.ResourceImporter{
 			State: importRepoRetentionRule,
 		},
@@ -136,10 +148,10 @@ func resourceRepoRetentionRule() *schema.Resource {
 			"repository": {
 				Type:         schema.TypeString,
 				Required:     true,
-				ForceNew:     true,
-				Description:  "The name of the repository.",
+				Description:  "The repository, identified by its slug or slug_perm.",
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
+			RepositorySlugPerm: repositorySlugPermSchema(),
 			"retention_count_limit": {
 				Type:         schema.TypeInt,
 				Optional:     true,
@@ -0,0 +1,291 @@
+package cloudsmith
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// signing key types
+const (
+	EcdsaKey = "ecdsa"
+	GpgKey   = "gpg"
+)
+
+type SigningKey interface {
+	GetActive() bool
+	GetCreatedAt() time.Time
+	GetDefault() bool
+	GetFingerprint() string
+	GetFingerprintShort() string
+	GetPublicKey() string
+}
+
+func importRepositorySigningKey(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	idParts := strings.Split(d.Id(), ".")
+	if len(idParts) != 2 {
+		return nil, fmt.Errorf(
+			"invalid import ID, must be of the form <organization_slug>.<repository_slug>, got: %s", d.Id(),
+		)
+	}
+
+	d.Set("namespace", idParts[0])
+	d.Set("repository", idParts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+// getSigningKey retrieves the currently active signing key of the given type
+// for a repository.
+func getSigningKey(pc *providerConfig, keyType, namespace, repository string) (SigningKey, *http.Response, error) {
+	switch keyType {
+	case EcdsaKey:
+		req := pc.APIClient.ReposApi.ReposEcdsaList(pc.Auth, namespace, repository)
+		return pc.APIClient.ReposApi.ReposEcdsaListExecute(req)
+	case GpgKey:
+		req := pc.APIClient.ReposApi.ReposGpgList(pc.Auth, namespace, repository)
+		return pc.APIClient.ReposApi.ReposGpgListExecute(req)
+	default:
+		return nil, nil, fmt.Errorf("invalid signing key type '%s'", keyType)
+	}
+}
+
+// regenerateSigningKey asks Cloudsmith to generate a brand new signing key of
+// the given type for a repository, replacing the currently active key.
+func regenerateSigningKey(pc *providerConfig, keyType, namespace, repository string) (SigningKey, error) {
+	switch keyType {
+	case EcdsaKey:
+		req := pc.APIClient.ReposApi.ReposEcdsaRegenerate(pc.Auth, namespace, repository)
+		key, _, err := pc.APIClient.ReposApi.ReposEcdsaRegenerateExecute(req)
+		return key, err
+	case GpgKey:
+		req := pc.APIClient.ReposApi.ReposGpgRegenerate(pc.Auth, namespace, repository)
+		key, _, err := pc.APIClient.ReposApi.ReposGpgRegenerateExecute(req)
+		return key, err
+	default:
+		return nil, fmt.Errorf("invalid signing key type '%s'", keyType)
+	}
+}
+
+// regeneratedSigningKeyAttributes are the attributes of a signing key which
+// change when it is regenerated.
+var regeneratedSigningKeyAttributes = []string{"created_at", "fingerprint", "fingerprint_short", "public_key"}
+
+// regenerateRequested returns whether the regenerate trigger was changed to a
+// new value. Removing it from the configuration doesn't regenerate the key.
+func regenerateRequested(d interface {
+	GetChange(string) (interface{}, interface{})
+}) bool {
+	oldValue, newValue := d.GetChange("regenerate")
+	return newValue.(string) != "" && newValue.(string) != oldValue.(string)
+}
+
+// resourceRepositorySigningKeyCustomizeDiff plans the details of the key as
+// unknown when it is going to be regenerated, so that anything depending on
+// them isn't planned against the old key.
+func resourceRepositorySigningKeyCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !regenerateRequested(d) {
+		return nil
+	}
+
+	for _, key := range regeneratedSigningKeyAttributes {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceRepositorySigningKeyCreate(keyType string) schema.CreateFunc {
+	return func(d *schema.ResourceData, m interface{}) error {
+		pc := m.(*providerConfig)
+
+		namespace := requiredString(d, "namespace")
+		if _, _, err := repositorySlugPerm(pc, d, namespace); err != nil {
+			return err
+		}
+
+		// Every repository is provisioned with signing keys when it is
+		// created, so there is nothing to create here; we simply start
+		// tracking the active key.
+		d.SetId(fmt.Sprintf("%s.%s", namespace, requiredString(d, "repository")))
+
+		return resourceRepositorySigningKeyRead(keyType)(d, m)
+	}
+}
+
+func resourceRepositorySigningKeyRead(keyType string) schema.ReadFunc {
+	return func(d *schema.ResourceData, m interface{}) error {
+		pc := m.(*providerConfig)
+
+		namespace := requiredString(d, "namespace")
+		repository, resp, err := repositorySlugPerm(pc, d, namespace)
+		if err != nil {
+			if is404(resp) {
+				d.SetId("")
+				return nil
+			}
+
+			return err
+		}
+
+		key, resp, err := getSigningKey(pc, keyType, namespace, repository)
+		if err != nil {
+			if is404(resp) {
+				d.SetId("")
+				return nil
+			}
+
+			return err
+		}
+
+		d.Set("active", key.GetActive())
+		d.Set("created_at", timeToString(key.GetCreatedAt()))
+		d.Set("default", key.GetDefault())
+		d.Set("fingerprint", key.GetFingerprint())
+		d.Set("fingerprint_short", key.GetFingerprintShort())
+		d.Set("public_key", key.GetPublicKey())
+
+		// namespace and repository are not returned from the signing key
+		// endpoints, so we can use the values stored in resource state.
+		// Changing namespace forces a new resource; requests are made with
+		// repository_slug_perm, so repository is kept as configured and only
+		// forces a new resource if it refers to another repository (see
+		// customizeDiffRepository).
+		d.Set("namespace", namespace)
+		d.Set("repository", requiredString(d, "repository"))
+
+		return nil
+	}
+}
+
+func resourceRepositorySigningKeyUpdate(keyType string) schema.UpdateFunc {
+	return func(d *schema.ResourceData, m interface{}) error {
+		pc := m.(*providerConfig)
+
+		namespace := requiredString(d, "namespace")
+		repository, _, err := repositorySlugPerm(pc, d, namespace)
+		if err != nil {
+			return err
+		}
+
+		if regenerateRequested(d) {
+			previous := requiredString(d, "fingerprint")
+
+			if _, err := regenerateSigningKey(pc, keyType, namespace, repository); err != nil {
+				return fmt.Errorf("error regenerating %s key for repository (%s): %w", keyType, repository, err)
+			}
+
+			checkerFunc := func() error {
+				key, _, err := getSigningKey(pc, keyType, namespace, repository)
+				if err != nil {
+					return err
+				}
+				if key.GetFingerprint() == previous {
+					return errKeepWaiting
+				}
+				return nil
+			}
+			if err := waiter(checkerFunc, defaultUpdateTimeout, defaultUpdateInterval); err != nil {
+				return fmt.Errorf("error waiting for %s key (%s) to be regenerated: %w", keyType, d.Id(), err)
+			}
+		}
+
+		return resourceRepositorySigningKeyRead(keyType)(d, m)
+	}
+}
+
+func resourceRepositorySigningKeyDelete(d *schema.ResourceData, m interface{}) error {
+	// Signing keys cannot be removed from a repository, so deleting the
+	// resource only stops Terraform from tracking the key.
+	return nil
+}
+
+//nolint:funlen
+func resourceRepositorySigningKey(keyType string) *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRepositorySigningKeyCreate(keyType),
+		Read:   resourceRepositorySigningKeyRead(keyType),
+		Update: resourceRepositorySigningKeyUpdate(keyType),
+		Delete: resourceRepositorySigningKeyDelete,
+
+		CustomizeDiff: customdiff.All(
+			customizeDiffRepository("namespace"),
+			resourceRepositorySigningKeyCustomizeDiff,
+		),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: importRepositorySigningKey,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"active": {
+				Type:        schema.TypeBool,
+				Description: "If enabled, the key is active and in use by the repository.",
+				Computed:    true,
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Description: "ISO 8601 timestamp at which the key was created.",
+				Computed:    true,
+			},
+			"default": {
+				Type:        schema.TypeBool,
+				Description: "If selected this is the default key for this repository.",
+				Computed:    true,
+			},
+			"fingerprint": {
+				Type:        schema.TypeString,
+				Description: "The long identifier used by the key.",
+				Computed:    true,
+			},
+			"fingerprint_short": {
+				Type:        schema.TypeString,
+				Description: "The short identifier used by the key.",
+				Computed:    true,
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace to which the repository belongs.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"public_key": {
+				Type:        schema.TypeString,
+				Description: "The public key given to repository users.",
+				Computed:    true,
+			},
+			"regenerate": {
+				Type: schema.TypeString,
+				Description: "An arbitrary value which, when changed to a new non-empty value, causes the " +
+					"repository's key to be regenerated. Use this to rotate the key, e.g. in response to a security incident.",
+				Optional: true,
+			},
+			"repository": {
+				Type:         schema.TypeString,
+				Description:  "Repository to which the key belongs, identified by its slug or slug_perm.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			RepositorySlugPerm: repositorySlugPermSchema(),
+		},
+	}
+}
+
+// resourceRepositoryEcdsaKey returns the resource tracking the ECDSA signing
+// key of a repository.
+func resourceRepositoryEcdsaKey() *schema.Resource {
+	return resourceRepositorySigningKey(EcdsaKey)
+}
+
+// resourceRepositoryGpgKey returns the resource tracking the GPG signing key
+// of a repository.
+func resourceRepositoryGpgKey() *schema.Resource {
+	return resourceRepositorySigningKey(GpgKey)
+}
@@ -0,0 +1,149 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestRepositorySigningKeyRegeneratePlanned verifies that the details of the
+// key are planned as unknown only when regenerate is changed to a new
+// non-empty value.
+func TestRepositorySigningKeyRegeneratePlanned(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		old         string
+		new         string
+		regenerated bool
+	}{
+		{"set", "", "2024-06-01", true},
+		{"changed", "2024-06-01", "2024-07-01", true},
+		{"unchanged", "2024-06-01", "2024-06-01", false},
+		{"removed", "2024-06-01", "", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := resourceRepositoryGpgKey()
+
+			config := func(regenerate string) map[string]interface{} {
+				raw := map[string]interface{}{"namespace": "my-org", "repository": "my-repo"}
+				if regenerate != "" {
+					raw["regenerate"] = regenerate
+				}
+				return raw
+			}
+
+			d := schema.TestResourceDataRaw(t, r.Schema, config(tc.old))
+			d.SetId("my-org.my-repo")
+			d.Set(RepositorySlugPerm, "AbCdEfGhIjKl")
+			d.Set("fingerprint", "OLDFINGERPRINT")
+
+			diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config(tc.new)), &providerConfig{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			for _, key := range regeneratedSigningKeyAttributes {
+				computed := diff != nil && diff.Attributes[key] != nil && diff.Attributes[key].NewComputed
+				if computed != tc.regenerated {
+					t.Errorf("expected %s to be planned as unknown: %t, got %t", key, tc.regenerated, computed)
+				}
+			}
+		})
+	}
+}
+
+// TestAccRepositorySigningKey_basic spins up a repository, starts tracking its
+// GPG and ECDSA keys and verifies the key details are populated. Then it bumps
+// the regenerate trigger on both keys and verifies that new keys have been
+// issued before tearing down the resources.
+func TestAccRepositorySigningKey_basic(t *testing.T) {
+	t.Parallel()
+
+	var gpgFingerprint, ecdsaFingerprint string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccRepositoryCheckDestroy("cloudsmith_repository.test"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRepositorySigningKeyConfig("initial"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("cloudsmith_repository_gpg_key.test", "fingerprint"),
+					resource.TestCheckResourceAttrSet("cloudsmith_repository_gpg_key.test", "public_key"),
+					resource.TestCheckResourceAttrSet("cloudsmith_repository_ecdsa_key.test", "fingerprint"),
+					resource.TestCheckResourceAttrSet("cloudsmith_repository_ecdsa_key.test", "public_key"),
+					testAccRepositorySigningKeyFingerprint("cloudsmith_repository_gpg_key.test", &gpgFingerprint),
+					testAccRepositorySigningKeyFingerprint("cloudsmith_repository_ecdsa_key.test", &ecdsaFingerprint),
+				),
+			},
+			{
+				Config: testAccRepositorySigningKeyConfig("rotated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccRepositorySigningKeyFingerprintChanged("cloudsmith_repository_gpg_key.test", &gpgFingerprint),
+					testAccRepositorySigningKeyFingerprintChanged("cloudsmith_repository_ecdsa_key.test", &ecdsaFingerprint),
+				),
+			},
+		},
+	})
+}
+
+func testAccRepositorySigningKeyFingerprint(resourceName string, fingerprint *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		*fingerprint = resourceState.Primary.Attributes["fingerprint"]
+		return nil
+	}
+}
+
+func testAccRepositorySigningKeyFingerprintChanged(resourceName string, fingerprint *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		if resourceState.Primary.Attributes["fingerprint"] == *fingerprint {
+			return fmt.Errorf("expected %s to have been regenerated, fingerprint unchanged: %s", resourceName, *fingerprint)
+		}
+		return nil
+	}
+}
+
+func testAccRepositorySigningKeyConfig(regenerate string) string {
+	return fmt.Sprintf(`
+resource "cloudsmith_repository" "test" {
+	name      = "terraform-acc-test-signing-key"
+	namespace = "%s"
+}
+
+resource "cloudsmith_repository_gpg_key" "test" {
+	namespace  = cloudsmith_repository.test.namespace
+	repository = cloudsmith_repository.test.slug_perm
+	regenerate = "%s"
+}
+
+resource "cloudsmith_repository_ecdsa_key" "test" {
+	namespace  = cloudsmith_repository.test.namespace
+	repository = cloudsmith_repository.test.slug_perm
+	regenerate = "%s"
+}
+`, os.Getenv("CLOUDSMITH_NAMESPACE"), regenerate, regenerate)
+}
@@ -2,6 +2,7 @@
 package cloudsmith
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"regexp"
@@ -11,6 +12,48 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
+// TestRepositoryOpenSourceRequiresProject verifies that open source
+// repositories can only be planned with the license and project URL required
+// by the API.
+func TestRepositoryOpenSourceRequiresProject(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"public", map[string]interface{}{"repository_type": "Public"}, false},
+		{"open source", map[string]interface{}{
+			"repository_type":         "Open-Source",
+			"open_source_license":     "Apache-2.0",
+			"open_source_project_url": "https://example.com",
+		}, false},
+		{"open source without license", map[string]interface{}{
+			"repository_type":         "Open-Source",
+			"open_source_project_url": "https://example.com",
+		}, true},
+		{"open source without project", map[string]interface{}{"repository_type": "Open-Source"}, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			raw := map[string]interface{}{"name": "my-repo", "namespace": "my-org"}
+			for k, v := range tc.config {
+				raw[k] = v
+			}
+
+			_, err := resourceRepository().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), &providerConfig{})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %t, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
 // TestAccRepository_basic spins up a repository with all default options,
 // verifies it exists and checks the name is set correctly. Then it changes the
 // name, and verifies it's been set correctly before tearing down the resource
@@ -98,27 +98,71 @@ type Upstream interface {
 	GetVerifySsl() bool
 }
 
-func importUpstream(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+// importUpstream imports an upstream by an ID of the form
+// <namespace>.<repository>.<upstream_type>.<slug_perm>, or
+// <namespace>.<repository>.<slug_perm> in which case the upstream type is
+// detected by looking the upstream up with each type in turn.
+func importUpstream(_ context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	idParts := strings.Split(d.Id(), ".")
-	if len(idParts) != 4 {
+
+	switch len(idParts) {
+	case 3:
+		_ = d.Set(Namespace, idParts[0])
+		_ = d.Set(Repository, idParts[1])
+		d.SetId(idParts[2])
+
+		upstreamType, err := detectUpstreamType(d, m)
+		if err != nil {
+			return nil, err
+		}
+		_ = d.Set(UpstreamType, upstreamType)
+	case 4:
+		_ = d.Set(Namespace, idParts[0])
+		_ = d.Set(Repository, idParts[1])
+		_ = d.Set(UpstreamType, idParts[2])
+		d.SetId(idParts[3])
+	default:
 		return nil, fmt.Errorf(
-			"invalid import ID, must be of the form <namespace_slug>.<repository_slug>.<upstream_type>.<upsteam_slug_perm>, got: %s", d.Id(),
+			"invalid import ID, must be of the form <namespace_slug>.<repository_slug>.<upstream_type>.<upsteam_slug_perm> "+
+				"or <namespace_slug>.<repository_slug>.<upsteam_slug_perm>, got: %s", d.Id(),
 		)
 	}
 
-	_ = d.Set(Namespace, idParts[0])
-	_ = d.Set(Repository, idParts[1])
-	_ = d.Set(UpstreamType, idParts[2])
-	d.SetId(idParts[3])
 	return []*schema.ResourceData{d}, nil
 }
 
+// detectUpstreamType returns the type of the upstream identified by the ID of
+// d, trying each type until one is found. Upstreams of different types are
+// managed through separate endpoints, so there is no way to look one up
+// without knowing its type.
+func detectUpstreamType(d *schema.ResourceData, m interface{}) (string, error) {
+	for _, upstreamType := range upstreamTypes {
+		_ = d.Set(UpstreamType, upstreamType)
+
+		_, resp, err := getUpstream(d, m)
+		if err == nil {
+			return upstreamType, nil
+		}
+		if !is404(resp) {
+			return "", fmt.Errorf("error looking up %s upstream %s: %w", upstreamType, d.Id(), err)
+		}
+	}
+
+	return "", fmt.Errorf(
+		"upstream %s not found in repository %s, or its upstream type isn't supported by this provider",
+		d.Id(), requiredString(d, Repository),
+	)
+}
+
 func resourceRepositoryUpstreamCreate(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, Namespace)
-	repository := requiredString(d, Repository)
 	upstreamType := requiredString(d, UpstreamType)
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
 
 	authMode := optionalString(d, AuthMode)
 	authSecret := nullableString(d, AuthSecret)
@@ -136,7 +180,6 @@ func resourceRepositoryUpstreamCreate(d *schema.ResourceData, m interface{}) err
 
 	var upstream Upstream
 	var resp *http.Response
-	var err error
 
 	switch upstreamType {
 	case Composer:
@@ -413,11 +456,12 @@ func getUpstream(d *schema.ResourceData, m interface{}) (Upstream, *http.Respons
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, Namespace)
-	repository := requiredString(d, Repository)
 	upstreamType := requiredString(d, UpstreamType)
+	repository, resp, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return nil, resp, err
+	}
 
-	var err error
-	var resp *http.Response
 	var upstream Upstream
 
 	switch upstreamType {
@@ -509,8 +553,11 @@ func resourceRepositoryUpstreamRead(d *schema.ResourceData, m interface{}) error
 	}
 
 	// namespace, repository and upstream_type are not returned from the read
-	// endpoint, so we can use the values stored in resource state. We rely on
-	// ForceNew to ensure that if any of these change then a new resource is created.
+	// endpoint, so we can use the values stored in resource state. Changing
+	// namespace or upstream_type forces a new resource; requests are made with
+	// repository_slug_perm, so repository is kept as configured and only
+	// forces a new resource if it refers to another repository (see
+	// customizeDiffRepository).
 	_ = d.Set(Namespace, requiredString(d, Namespace))
 	_ = d.Set(Repository, requiredString(d, Repository))
 	_ = d.Set(UpstreamType, requiredString(d, UpstreamType))
@@ -522,8 +569,12 @@ func resourceRepositoryUpstreamUpdate(d *schema.ResourceData, m interface{}) err
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, Namespace)
-	repository := requiredString(d, Repository)
 	upstreamType := requiredString(d, UpstreamType)
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
+
 	slugPerm := d.Id()
 
 	authMode := optionalString(d, AuthMode)
@@ -541,7 +592,6 @@ func resourceRepositoryUpstreamUpdate(d *schema.ResourceData, m interface{}) err
 	verifySsl := optionalBool(d, VerifySsl)
 
 	var upstream Upstream
-	var err error
 
 	switch upstreamType {
 	case Composer:
@@ -814,10 +864,11 @@ func resourceRepositoryUpstreamDelete(d *schema.ResourceData, m interface{}) err
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, Namespace)
-	repository := requiredString(d, Repository)
 	upstreamType := requiredString(d, UpstreamType)
-
-	var err error
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
 
 	switch upstreamType {
 	case Composer:
@@ -898,6 +949,8 @@ func resourceRepositoryUpstream() *schema.Resource {
 		Update: resourceRepositoryUpstreamUpdate,
 		Delete: resourceRepositoryUpstreamDelete,
 
+		CustomizeDiff: customizeDiffRepository(Namespace),
+
 		Importer: &schema.ResourceImporter{
 			StateContext: importUpstream,
 		},
@@ -1014,11 +1067,11 @@ func resourceRepositoryUpstream() *schema.Resource {
 			},
 			Repository: {
 				Type:         schema.TypeString,
-				Description:  "The Repository to which the Upstream belongs.",
+				Description:  "The Repository to which the Upstream belongs, identified by its slug or slug_perm.",
 				Required:     true,
-				ForceNew:     true,
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
+			RepositorySlugPerm: repositorySlugPermSchema(),
 			SlugPerm: {
 				Type:        schema.TypeString,
 				Description: "The unique identifier for this Upstream.",
@@ -215,6 +215,21 @@ resource "cloudsmith_repository_upstream" "ubuntu" {
 				},
 				ImportStateVerify: true,
 			},
+			{
+				// without the upstream type, which is detected
+				ResourceName: debUpstreamResourceName,
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					resourceState := s.RootModule().Resources[debUpstreamResourceName]
+					return fmt.Sprintf(
+						"%s.%s.%s",
+						resourceState.Primary.Attributes[Namespace],
+						resourceState.Primary.Attributes[Repository],
+						resourceState.Primary.Attributes[SlugPerm],
+					), nil
+				},
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
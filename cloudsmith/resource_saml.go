@@ -3,12 +3,15 @@ package cloudsmith
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/cloudsmith-io/cloudsmith-api-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
 )
 
 func samlImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
@@ -21,32 +24,109 @@ func samlImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*
 
 	d.Set("organization", idParts[0])
 	d.SetId(idParts[1])
+	// lifecycle_hint only affects creation, so start from its default
+	d.Set(LifecycleHint, lifecycleHintFailOnConflict)
 	return []*schema.ResourceData{d}, nil
 }
 
+// resolveOrganizationSlugPerm returns the slug_perm of an organization, which
+// unlike its slug never changes.
+func resolveOrganizationSlugPerm(pc *providerConfig, organization string) (string, *http.Response, error) {
+	req := pc.APIClient.OrgsApi.OrgsRead(pc.Auth, organization)
+	org, resp, err := pc.APIClient.OrgsApi.OrgsReadExecute(req)
+	if err != nil {
+		return "", resp, err
+	}
+
+	return org.GetSlugPerm(), resp, nil
+}
+
+// samlCustomizeDiff replaces the group sync when its organization changes,
+// unless the new value refers to the same organization as before (e.g. its
+// slug was renamed, or only the casing differs).
+func samlCustomizeDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("organization") {
+		return nil
+	}
+
+	if !d.NewValueKnown("organization") {
+		return d.ForceNew("organization")
+	}
+
+	oldOrganization, newOrganization := d.GetChange("organization")
+	if strings.EqualFold(oldOrganization.(string), newOrganization.(string)) {
+		return nil
+	}
+
+	pc := m.(*providerConfig)
+
+	// any failure to resolve the new value means it can't be the same
+	// organization
+	slugPerm, _, err := resolveOrganizationSlugPerm(pc, newOrganization.(string))
+	if err != nil || slugPerm != d.Get("organization_slug_perm").(string) {
+		return d.ForceNew("organization")
+	}
+
+	return nil
+}
+
+// samlOrganization returns how to refer to the organization of a group sync
+// in API requests: by its slug_perm, which the API accepts and which survives
+// the organization's slug being renamed, or by the organization attribute if
+// the slug_perm isn't known yet.
+func samlOrganization(d *schema.ResourceData) string {
+	if slugPerm := d.Get("organization_slug_perm").(string); slugPerm != "" {
+		return slugPerm
+	}
+	return requiredString(d, "organization")
+}
+
 func samlCreate(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	organization := requiredString(d, "organization")
-	req := pc.APIClient.OrgsApi.OrgsSamlGroupSyncCreate(pc.Auth, organization)
-	req = req.Data(cloudsmith.OrganizationGroupSyncRequest{
+
+	organizationSlugPerm, _, err := resolveOrganizationSlugPerm(pc, organization)
+	if err != nil {
+		return err
+	}
+	d.Set("organization_slug_perm", organizationSlugPerm)
+
+	requireExistingTeam := requiredBool(d, "require_existing_team")
+	if requireExistingTeam {
+		if err := checkTeamExists(pc, organization, requiredString(d, "team")); err != nil {
+			return err
+		}
+	}
+
+	data := cloudsmith.OrganizationGroupSyncRequest{
 		IdpKey:       requiredString(d, "idp_key"),
 		IdpValue:     requiredString(d, "idp_value"),
 		Role:         optionalString(d, "role"), // default to Member
 		Team:         requiredString(d, "team"),
 		Organization: requiredString(d, "organization"),
-	})
+	}
 
-	saml, _, err := pc.APIClient.OrgsApi.OrgsSamlGroupSyncCreateExecute(req)
+	saml, resp, err := pc.SAML.CreateGroupSync(organization, data)
 	if err != nil {
-		return err
+		if !adoptOnConflict(d, pc, resp) {
+			return err
+		}
+		if saml, err = adoptGroupSync(pc, organization, data, err); err != nil {
+			return err
+		}
 	}
 
 	d.SetId(saml.GetSlugPerm())
 
+	// the waiter only exists to report missing teams, which have already
+	// been checked for
+	if requireExistingTeam {
+		return samlRead(d, m)
+	}
+
 	checkerFunc := func() error {
-		req := pc.APIClient.OrgsApi.OrgsSamlGroupSyncList(pc.Auth, organization)
-		_, resp, err := pc.APIClient.OrgsApi.OrgsSamlGroupSyncListExecute(req)
+		_, resp, err := pc.SAML.ListGroupSyncs(organization, 0, 0)
 		if err != nil {
 			if resp != nil {
 				if is404(resp) {
@@ -69,11 +149,7 @@ func samlCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func retrieveSAMLSyncListPage(pc *providerConfig, organization string, pageSize int64, pageCount int64) ([]cloudsmith.OrganizationGroupSync, int64, error) {
-	req := pc.APIClient.OrgsApi.OrgsSamlGroupSyncList(pc.Auth, organization)
-	req = req.Page(pageCount)
-	req = req.PageSize(pageSize)
-
-	samlPage, resp, err := pc.APIClient.OrgsApi.OrgsSamlGroupSyncListExecute(req)
+	samlPage, resp, err := pc.SAML.ListGroupSyncs(organization, pageCount, pageSize)
 	if err != nil {
 		if is404(resp) {
 			return nil, 0, nil
@@ -132,8 +208,18 @@ func samlRead(d *schema.ResourceData, m interface{}) error {
 
 	organization := requiredString(d, "organization")
 
+	// organization_slug_perm won't be known after import, or when upgrading
+	// from an earlier provider version
+	if d.Get("organization_slug_perm").(string) == "" {
+		organizationSlugPerm, _, err := resolveOrganizationSlugPerm(pc, organization)
+		if err != nil {
+			return err
+		}
+		d.Set("organization_slug_perm", organizationSlugPerm)
+	}
+
 	var pageCount, pageSize int64 = -1, -1
-	samlList, err := retrieveSAMLSyncListPages(pc, organization, pageSize, pageCount)
+	samlList, err := retrieveSAMLSyncListPages(pc, samlOrganization(d), pageSize, pageCount)
 	if err != nil {
 		return err
 	}
@@ -160,48 +246,117 @@ func samlRead(d *schema.ResourceData, m interface{}) error {
 
 func samlDelete(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
-	organization := requiredString(d, "organization")
+	organization := samlOrganization(d)
 
-	req := pc.APIClient.OrgsApi.OrgsSamlGroupSyncDelete(pc.Auth, organization, d.Id())
-	_, err := pc.APIClient.OrgsApi.OrgsSamlGroupSyncDeleteExecute(req)
+	_, err := pc.SAML.DeleteGroupSync(organization, d.Id())
 	if err != nil {
 		return err
 	}
 
+	return waitForGroupSyncDeletion(pc, organization, d.Id())
+}
+
+// waitForGroupSyncDeletion waits until a deleted group sync is no longer
+// listed, so that recreating it (e.g. in samlUpdate) doesn't conflict with it.
+func waitForGroupSyncDeletion(pc *providerConfig, organization, slugPerm string) error {
 	checkerFunc := func() error {
-		req := pc.APIClient.OrgsApi.OrgsSamlGroupSyncList(pc.Auth, organization)
-		_, resp, err := pc.APIClient.OrgsApi.OrgsSamlGroupSyncListExecute(req)
+		samlList, err := retrieveSAMLSyncListPages(pc, organization, -1, -1)
 		if err != nil {
-			if resp != nil {
-				if is404(resp) {
-					return nil
-				}
-			}
 			return err
 		}
+		if lo.ContainsBy(samlList, func(item cloudsmith.OrganizationGroupSync) bool {
+			return item.GetSlugPerm() == slugPerm
+		}) {
+			return errKeepWaiting
+		}
 		return nil
 	}
 
 	if err := waiter(checkerFunc, defaultDeletionTimeout, defaultDeletionInterval); err != nil {
-		return fmt.Errorf("error waiting for SAML group sync (%s) to be deleted: %w", d.Id(), err)
+		return fmt.Errorf("error waiting for SAML group sync (%s) to be deleted: %w", slugPerm, err)
 	}
 	return nil
 }
 
+// adoptGroupSync resolves a conflict creating a group sync by adopting the
+// existing group sync with the same idp_key, idp_value and team. There's no
+// update endpoint, so if its role differs it's deleted and created again.
+// createErr is returned if there is no such group sync.
+func adoptGroupSync(
+	pc *providerConfig, organization string, data cloudsmith.OrganizationGroupSyncRequest, createErr error,
+) (*cloudsmith.OrganizationGroupSync, error) {
+	samlList, err := retrieveSAMLSyncListPages(pc, organization, -1, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, ok := lo.Find(samlList, func(item cloudsmith.OrganizationGroupSync) bool {
+		return item.IdpKey == data.IdpKey && item.IdpValue == data.IdpValue && item.Team == data.Team
+	})
+	if !ok {
+		return nil, createErr
+	}
+
+	if existing.GetRole() == data.GetRole() {
+		return &existing, nil
+	}
+
+	if _, err := pc.SAML.DeleteGroupSync(organization, existing.GetSlugPerm()); err != nil {
+		return nil, fmt.Errorf("error deleting conflicting SAML group sync (%s): %w", existing.GetSlugPerm(), err)
+	}
+	if err := waitForGroupSyncDeletion(pc, organization, existing.GetSlugPerm()); err != nil {
+		return nil, err
+	}
+
+	saml, _, err := pc.SAML.CreateGroupSync(organization, data)
+	return saml, err
+}
+
 // This is a workaround for not having a proper update endpoint for SAML group sync, we are recreating the entry based on new+old values
 func samlUpdate(d *schema.ResourceData, m interface{}) error {
+	// organization can only change without forcing a new resource if it
+	// still refers to the same organization, so there's nothing to recreate
+	if !d.HasChangesExcept("organization", "namespace", "require_existing_team", LifecycleHint) {
+		return samlRead(d, m)
+	}
+
 	if err := samlDelete(d, m); err != nil {
 		return err
 	}
 	return samlCreate(d, m)
 }
 
+// checkTeamExists returns an error listing the teams of an organization if it
+// has no team with the given slug or slug_perm.
+func checkTeamExists(pc *providerConfig, organization, team string) error {
+	teams, err := retrieveTeams(pc, organization)
+	if err != nil {
+		return fmt.Errorf("error retrieving teams of %s: %w", organization, err)
+	}
+
+	for _, t := range teams {
+		if t.GetSlug() == team || t.GetSlugPerm() == team {
+			return nil
+		}
+	}
+
+	slugs := lo.Map(teams, func(t cloudsmith.OrganizationTeam, _ int) string {
+		return t.GetSlug()
+	})
+	sort.Strings(slugs)
+
+	return fmt.Errorf("team %q does not exist in organization %s, valid teams are: %s", team, organization, strings.Join(slugs, ", "))
+}
+
 func resourceSAML() *schema.Resource {
 	return &schema.Resource{
-		Create: samlCreate,
-		Read:   samlRead,
-		Update: samlUpdate,
-		Delete: samlDelete,
+		Create:      samlCreate,
+		ReadContext: readWithDriftDetection(samlRead, "idp_key", "idp_value", "role", "team"),
+		Update:      samlUpdate,
+		Delete:      samlDelete,
+
+		CustomizeDiff: samlCustomizeDiff,
+
 		Importer: &schema.ResourceImporter{
 			StateContext: samlImport,
 		},
@@ -209,7 +364,10 @@ func resourceSAML() *schema.Resource {
 			"organization": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
+			},
+			"organization_slug_perm": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 			"idp_key": {
 				Type:     schema.TypeString,
@@ -233,6 +391,12 @@ func resourceSAML() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"require_existing_team": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			LifecycleHint: lifecycleHintSchema(),
 		},
 	}
 }
@@ -0,0 +1,310 @@
+package cloudsmith
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/samber/lo"
+)
+
+// samlGroupSyncMapping identifies a single SAML group sync within an
+// organization for a given IdP key. There is no update endpoint for group
+// syncs, so a mapping is either present exactly as configured or not at all.
+type samlGroupSyncMapping struct {
+	IdpValue string
+	Team     string
+	Role     string
+}
+
+// expandSAMLGroupSyncMappings converts "mapping" blocks from TF state to a
+// slice of mappings.
+func expandSAMLGroupSyncMappings(set *schema.Set) []samlGroupSyncMapping {
+	return lo.Map(set.List(), func(x interface{}, index int) samlGroupSyncMapping {
+		m := x.(map[string]interface{})
+		return samlGroupSyncMapping{
+			IdpValue: m["idp_value"].(string),
+			Team:     m["team"].(string),
+			Role:     m["role"].(string),
+		}
+	})
+}
+
+// flattenSAMLGroupSyncMappings converts a slice of mappings to a *schema.Set
+// that can be stored in TF state.
+func flattenSAMLGroupSyncMappings(mappings []samlGroupSyncMapping) *schema.Set {
+	mappingSchema := resourceSAMLGroupSyncs().Schema["mapping"].Elem.(*schema.Resource)
+	set := schema.NewSet(schema.HashResource(mappingSchema), []interface{}{})
+
+	for _, mapping := range mappings {
+		set.Add(map[string]interface{}{
+			"idp_value": mapping.IdpValue,
+			"team":      mapping.Team,
+			"role":      mapping.Role,
+		})
+	}
+
+	return set
+}
+
+// retrieveSAMLGroupSyncMappings returns the slug_perm of every group sync in
+// the organization using the given IdP key, indexed by mapping.
+func retrieveSAMLGroupSyncMappings(pc *providerConfig, organization, idpKey string) (map[samlGroupSyncMapping]string, error) {
+	var pageCount, pageSize int64 = -1, -1
+	samlList, err := retrieveSAMLSyncListPages(pc, organization, pageSize, pageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[samlGroupSyncMapping]string{}
+	for _, item := range samlList {
+		if item.GetIdpKey() != idpKey {
+			continue
+		}
+
+		mapping := samlGroupSyncMapping{IdpValue: item.GetIdpValue(), Team: item.GetTeam(), Role: item.GetRole()}
+		existing[mapping] = item.GetSlugPerm()
+	}
+
+	return existing, nil
+}
+
+// samlGroupSyncsReconcile creates and deletes group syncs so that those
+// managed by the resource match its configuration. Only mappings which have
+// changed are touched, so large configurations can be applied with a single
+// list request plus one request per changed mapping. Deletions beyond
+// max_removals_per_apply are deferred, and those mappings kept in state.
+func samlGroupSyncsReconcile(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	pc := m.(*providerConfig)
+
+	organization := requiredString(d, "organization")
+	idpKey := requiredString(d, "idp_key")
+
+	if d.IsNewResource() && requiredBool(d, EnsureFeatureEnabled) {
+		if _, err := pc.SAML.EnableGroupSync(organization); err != nil {
+			return diag.Errorf("error enabling SAML group sync for %s: %s", organization, err)
+		}
+	}
+
+	oldMappings, newMappings := d.GetChange("mapping")
+	oldSet := oldMappings.(*schema.Set)
+	newSet := newMappings.(*schema.Set)
+
+	toDelete := expandSAMLGroupSyncMappings(oldSet.Difference(newSet))
+	toCreate := expandSAMLGroupSyncMappings(newSet.Difference(oldSet))
+
+	sort.Slice(toDelete, func(i, j int) bool {
+		if toDelete[i].IdpValue != toDelete[j].IdpValue {
+			return toDelete[i].IdpValue < toDelete[j].IdpValue
+		}
+		return toDelete[i].Team < toDelete[j].Team
+	})
+	toDelete, deferred := deferRemovals(d, toDelete)
+
+	existing, err := retrieveSAMLGroupSyncMappings(pc, organization, idpKey)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, mapping := range toDelete {
+		slugPerm, ok := existing[mapping]
+		if !ok {
+			// already removed outside of Terraform
+			continue
+		}
+
+		if resp, err := pc.SAML.DeleteGroupSync(organization, slugPerm); err != nil && !is404(resp) {
+			return diag.Errorf("error deleting SAML group sync for %s: %s", mapping.IdpValue, err)
+		}
+	}
+
+	for _, mapping := range toCreate {
+		if _, ok := existing[mapping]; ok {
+			continue
+		}
+
+		data := cloudsmith.OrganizationGroupSyncRequest{
+			IdpKey:       idpKey,
+			IdpValue:     mapping.IdpValue,
+			Role:         cloudsmith.PtrString(mapping.Role),
+			Team:         mapping.Team,
+			Organization: organization,
+		}
+		if _, resp, err := pc.SAML.CreateGroupSync(organization, data); err != nil {
+			if resp != nil && resp.StatusCode == 422 {
+				return diag.Errorf("error creating SAML group sync for %s, please check that team %s exists: %s", mapping.IdpValue, mapping.Team, err)
+			}
+			return diag.Errorf("error creating SAML group sync for %s: %s", mapping.IdpValue, err)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", organization, idpKey))
+
+	checkerFunc := func() error {
+		existing, err := retrieveSAMLGroupSyncMappings(pc, organization, idpKey)
+		if err != nil {
+			return err
+		}
+		for _, mapping := range toDelete {
+			if _, ok := existing[mapping]; ok {
+				return errKeepWaiting
+			}
+		}
+		for _, mapping := range toCreate {
+			if _, ok := existing[mapping]; !ok {
+				return errKeepWaiting
+			}
+		}
+		return nil
+	}
+	if err := waiter(checkerFunc, defaultUpdateTimeout, defaultUpdateInterval); err != nil {
+		return diag.Errorf("error waiting for SAML group syncs (%s) to be updated: %s", d.Id(), err)
+	}
+
+	if err := samlGroupSyncsRead(d, m); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// mappings whose deletion was deferred are still managed, so they're
+	// deleted by the next apply unless they're configured again
+	if len(deferred) > 0 {
+		mappings := d.Get("mapping").(*schema.Set)
+		for _, mapping := range flattenSAMLGroupSyncMappings(deferred).List() {
+			mappings.Add(mapping)
+		}
+		d.Set("mapping", mappings)
+	}
+
+	return deferredRemovalsWarning(d, "SAML group sync mappings", len(deferred))
+}
+
+func samlGroupSyncsRead(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	organization := requiredString(d, "organization")
+	idpKey := requiredString(d, "idp_key")
+
+	existing, err := retrieveSAMLGroupSyncMappings(pc, organization, idpKey)
+	if err != nil {
+		return err
+	}
+
+	// Only track the mappings this resource manages, so group syncs created
+	// elsewhere (e.g. by cloudsmith_saml resources) are left alone. Managed
+	// mappings removed outside of Terraform drop out of state and are
+	// recreated on the next apply.
+	managed := lo.Filter(expandSAMLGroupSyncMappings(d.Get("mapping").(*schema.Set)), func(mapping samlGroupSyncMapping, index int) bool {
+		_, ok := existing[mapping]
+		return ok
+	})
+
+	d.Set("mapping", flattenSAMLGroupSyncMappings(managed))
+
+	// organization and idp_key are not returned from the saml group endpoint
+	// so we rely on the input values
+	d.Set("organization", organization)
+	d.Set("idp_key", idpKey)
+
+	return nil
+}
+
+func samlGroupSyncsDelete(d *schema.ResourceData, m interface{}) error {
+	pc := m.(*providerConfig)
+
+	organization := requiredString(d, "organization")
+	idpKey := requiredString(d, "idp_key")
+
+	existing, err := retrieveSAMLGroupSyncMappings(pc, organization, idpKey)
+	if err != nil {
+		return err
+	}
+
+	for _, mapping := range expandSAMLGroupSyncMappings(d.Get("mapping").(*schema.Set)) {
+		slugPerm, ok := existing[mapping]
+		if !ok {
+			continue
+		}
+
+		if resp, err := pc.SAML.DeleteGroupSync(organization, slugPerm); err != nil && !is404(resp) {
+			return fmt.Errorf("error deleting SAML group sync for %s: %w", mapping.IdpValue, err)
+		}
+	}
+
+	if requiredBool(d, DisableFeatureOnDestroy) {
+		// group sync is enabled for the whole organization, so it's left
+		// enabled for any group syncs managed elsewhere
+		remaining, err := retrieveSAMLSyncListPages(pc, organization, -1, -1)
+		if err != nil {
+			return err
+		}
+		if len(remaining) == 0 {
+			if _, err := pc.SAML.DisableGroupSync(organization); err != nil {
+				return fmt.Errorf("error disabling SAML group sync for %s: %w", organization, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+//nolint:funlen
+func resourceSAMLGroupSyncs() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: samlGroupSyncsReconcile,
+		Read:          samlGroupSyncsRead,
+		UpdateContext: samlGroupSyncsReconcile,
+		Delete:        samlGroupSyncsDelete,
+
+		Schema: map[string]*schema.Schema{
+			EnsureFeatureEnabled:    ensureFeatureEnabledSchema("SAML group sync for the organization", false),
+			DisableFeatureOnDestroy: disableFeatureOnDestroySchema("SAML group sync for the organization"),
+			MaxRemovalsPerApply:     maxRemovalsPerApplySchema("mappings"),
+			"organization": {
+				Type:         schema.TypeString,
+				Description:  "Organization to which the SAML group syncs belong.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"idp_key": {
+				Type:         schema.TypeString,
+				Description:  "The attribute key from your identity provider shared by all of the mappings.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"mapping": {
+				Type:        schema.TypeSet,
+				Description: "A mapping of an attribute value from your identity provider to a team.",
+				Required:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"idp_value": {
+							Type:         schema.TypeString,
+							Description:  "The attribute value from your identity provider.",
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"role": {
+							Type:         schema.TypeString,
+							Description:  "The role assigned for the team.",
+							Optional:     true,
+							Default:      "Member",
+							ValidateFunc: validation.StringInSlice([]string{"Member", "Manager"}, false),
+						},
+						"team": {
+							Type:         schema.TypeString,
+							Description:  "The team associated with the mapping. The team must already exist.",
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,99 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestAccSamlGroupSyncs_basic creates a set of SAML group sync mappings, then
+// changes one mapping, removes another and adds a new one, verifying the
+// group syncs in Cloudsmith match the configuration after each step.
+func TestAccSamlGroupSyncs_basic(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccSamlGroupSyncsCheckMappings("cloudsmith_saml_group_syncs.test", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSamlGroupSyncsConfig(`
+	mapping {
+		idp_value = "test-group-a"
+		team      = cloudsmith_team.test.slug
+	}
+	mapping {
+		idp_value = "test-group-b"
+		team      = cloudsmith_team.test.slug
+	}`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudsmith_saml_group_syncs.test", "mapping.#", "2"),
+					testAccSamlGroupSyncsCheckMappings("cloudsmith_saml_group_syncs.test", 2),
+				),
+			},
+			{
+				Config: testAccSamlGroupSyncsConfig(`
+	mapping {
+		idp_value = "test-group-a"
+		team      = cloudsmith_team.test.slug
+		role      = "Manager"
+	}
+	mapping {
+		idp_value = "test-group-c"
+		team      = cloudsmith_team.test.slug
+	}`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudsmith_saml_group_syncs.test", "mapping.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("cloudsmith_saml_group_syncs.test", "mapping.*", map[string]string{
+						"idp_value": "test-group-a",
+						"role":      "Manager",
+					}),
+					testAccSamlGroupSyncsCheckMappings("cloudsmith_saml_group_syncs.test", 2),
+				),
+			},
+		},
+	})
+}
+
+// testAccSamlGroupSyncsCheckMappings verifies the number of group syncs which
+// exist in Cloudsmith for the resource's IdP key.
+func testAccSamlGroupSyncsCheckMappings(resourceName string, expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		pc := testAccProvider.Meta().(*providerConfig)
+		existing, err := retrieveSAMLGroupSyncMappings(pc, rs.Primary.Attributes["organization"], rs.Primary.Attributes["idp_key"])
+		if err != nil {
+			return fmt.Errorf("error listing SAML group syncs: %w", err)
+		}
+
+		if len(existing) != expected {
+			return fmt.Errorf("expected %d SAML group syncs, found %d", expected, len(existing))
+		}
+
+		return nil
+	}
+}
+
+func testAccSamlGroupSyncsConfig(mappings string) string {
+	return fmt.Sprintf(`
+resource "cloudsmith_team" "test" {
+	organization = "%s"
+	name         = "test-team-group-syncs"
+}
+
+resource "cloudsmith_saml_group_syncs" "test" {
+	organization = "%s"
+	idp_key      = "test-idp-key-group-syncs"
+%s
+}
+`, os.Getenv("CLOUDSMITH_NAMESPACE"), os.Getenv("CLOUDSMITH_NAMESPACE"), mappings)
+}
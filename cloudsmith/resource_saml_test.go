@@ -1,11 +1,17 @@
 package cloudsmith
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/cloudsmith-io/cloudsmith-api-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -151,3 +157,80 @@ resource "cloudsmith_saml" "test" {
 	role 		= "Manager"
 	team 		= cloudsmith_team.test.slug
 }`, os.Getenv("CLOUDSMITH_NAMESPACE"), os.Getenv("CLOUDSMITH_NAMESPACE"))
+
+func TestCheckTeamExists(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/my-org/teams/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"name": "Ops", "slug": "ops", "slug_perm": "AbCdEfGhIjKl"}, {"name": "Dev", "slug": "dev", "slug_perm": "MnOpQrStUvWx"}]`)
+	}))
+	defer server.Close()
+
+	pc, diags := newProviderConfig(context.Background(), server.URL, "api-key", "test", httpClientOptions{})
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	for _, team := range []string{"ops", "MnOpQrStUvWx"} {
+		if err := checkTeamExists(pc, "my-org", team); err != nil {
+			t.Errorf("unexpected error for team %q: %s", team, err)
+		}
+	}
+
+	err := checkTeamExists(pc, "my-org", "qa")
+	if err == nil || !strings.Contains(err.Error(), "valid teams are: dev, ops") {
+		t.Errorf("expected an error listing the valid teams, got %v", err)
+	}
+}
+
+// TestSAMLReadRenamedOrganization refreshes and deletes a group sync whose
+// organization's slug has been renamed since it was created, verifying that
+// the organization is addressed by its slug_perm so that the group sync isn't
+// dropped from state.
+func TestSAMLReadRenamedOrganization(t *testing.T) {
+	t.Parallel()
+
+	// the old slug is no longer known to the API
+	saml := &fakeSAMLService{
+		organization: "OrGsLuGpErM",
+		syncs: []cloudsmith.OrganizationGroupSync{{
+			IdpKey:   "groups",
+			IdpValue: "developers",
+			Role:     cloudsmith.PtrString("Member"),
+			SlugPerm: cloudsmith.PtrString("sync-0"),
+			Team:     "dev",
+		}},
+	}
+	pc := &providerConfig{SAML: saml}
+
+	d := schema.TestResourceDataRaw(t, resourceSAML().Schema, map[string]interface{}{
+		"organization": "old-org-slug",
+		"idp_key":      "groups",
+		"idp_value":    "developers",
+		"team":         "dev",
+	})
+	d.SetId("sync-0")
+	d.Set("organization_slug_perm", "OrGsLuGpErM")
+
+	if err := samlRead(d, pc); err != nil {
+		t.Fatalf("unexpected error reading: %s", err)
+	}
+	if d.Id() != "sync-0" {
+		t.Fatal("expected the group sync to stay in state")
+	}
+	if got := d.Get("organization").(string); got != "old-org-slug" {
+		t.Errorf("expected organization to be left as configured, got %q", got)
+	}
+
+	if err := samlDelete(d, pc); err != nil {
+		t.Fatalf("unexpected error deleting: %s", err)
+	}
+	if len(saml.syncs) != 0 {
+		t.Errorf("expected the group sync to be deleted, got %v", saml.syncs)
+	}
+}
@@ -108,7 +108,7 @@ func resourceServiceCreate(ctx context.Context, d *schema.ResourceData, m interf
 	if requiredBool(d, "store_api_key") {
 		d.Set("key", service.GetKey())
 	} else {
-		d.Set("key", "**redacted**")
+		d.Set("key", redactedValue)
 	}
 	checkerFunc := func() error {
 		req := pc.APIClient.OrgsApi.OrgsServicesRead(pc.Auth, org, d.Id())
@@ -222,7 +222,7 @@ func resourceServiceUpdate(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.Errorf("error waiting for service (%s) to be updated: %s", d.Id(), err)
 	}
 	if !requiredBool(d, "store_api_key") {
-		d.Set("key", "**redacted**")
+		d.Set("key", redactedValue)
 	}
 	return resourceServiceRead(ctx, d, m)
 }
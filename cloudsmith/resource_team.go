@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"time"
 
@@ -180,6 +181,16 @@ func resourceTeamDelete(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
+// retrieveTeams returns every team in an organization.
+func retrieveTeams(pc *providerConfig, organization string) ([]cloudsmith.OrganizationTeam, error) {
+	return retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.OrganizationTeam, *http.Response, error) {
+		req := pc.APIClient.OrgsApi.OrgsTeamsList(pc.Auth, organization)
+		req = req.Page(page)
+		req = req.PageSize(pageSize)
+		return pc.APIClient.OrgsApi.OrgsTeamsListExecute(req)
+	})
+}
+
 //nolint:funlen
 func resourceTeam() *schema.Resource {
 	return &schema.Resource{
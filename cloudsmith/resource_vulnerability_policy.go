@@ -221,9 +221,10 @@ func resourceVulnerabilityPolicy() *schema.Resource {
 				Computed:    true,
 			},
 			PackageQueryString: {
-				Type:        schema.TypeString,
-				Description: "A search / filter string of packages to include in the policy.",
-				Optional:    true,
+				Type:             schema.TypeString,
+				Description:      "A search / filter string of packages to include in the policy.",
+				Optional:         true,
+				ValidateDiagFunc: validatePackageQuery,
 			},
 			UpdatedAt: {
 				Type:        schema.TypeString,
@@ -3,6 +3,7 @@ package cloudsmith
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -147,6 +148,8 @@ func importWebhook(ctx context.Context, d *schema.ResourceData, m interface{}) (
 	d.Set("namespace", idParts[0])
 	d.Set("repository", idParts[1])
 	d.SetId(idParts[2])
+	// lifecycle_hint only affects creation, so start from its default
+	d.Set(LifecycleHint, lifecycleHintFailOnConflict)
 	return []*schema.ResourceData{d}, nil
 }
 
@@ -154,7 +157,10 @@ func resourceWebhookCreate(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, "namespace")
-	repository := requiredString(d, "repository")
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
 
 	req := pc.APIClient.WebhooksApi.WebhooksCreate(pc.Auth, namespace, repository)
 	req = req.Data(cloudsmith.RepositoryWebhookRequest{
@@ -172,9 +178,12 @@ func resourceWebhookCreate(d *schema.ResourceData, m interface{}) error {
 		VerifySsl:                 optionalBool(d, "verify_ssl"),
 	})
 
-	webhook, _, err := pc.APIClient.WebhooksApi.WebhooksCreateExecute(req)
+	webhook, resp, err := pc.APIClient.WebhooksApi.WebhooksCreateExecute(req)
 	if err != nil {
-		return err
+		if !adoptOnConflict(d, pc, resp) {
+			return err
+		}
+		return adoptWebhook(d, m, namespace, repository, err)
 	}
 
 	d.SetId(webhook.GetSlugPerm())
@@ -196,11 +205,47 @@ func resourceWebhookCreate(d *schema.ResourceData, m interface{}) error {
 	return resourceWebhookRead(d, m)
 }
 
+// adoptWebhook resolves a conflict creating a webhook by adopting the existing
+// webhook with the same target URL and updating it to match the
+// configuration. createErr is returned if there is no such webhook.
+func adoptWebhook(d *schema.ResourceData, m interface{}, namespace, repository string, createErr error) error {
+	pc := m.(*providerConfig)
+
+	webhooks, err := retrieveAllPages(func(page, pageSize int64) ([]cloudsmith.RepositoryWebhook, *http.Response, error) {
+		req := pc.APIClient.WebhooksApi.WebhooksList(pc.Auth, namespace, repository)
+		req = req.Page(page)
+		req = req.PageSize(pageSize)
+		return pc.APIClient.WebhooksApi.WebhooksListExecute(req)
+	})
+	if err != nil {
+		return fmt.Errorf("error retrieving webhooks: %w", err)
+	}
+
+	existing, ok := lo.Find(webhooks, func(w cloudsmith.RepositoryWebhook) bool {
+		return w.GetTargetUrl() == requiredString(d, "target_url")
+	})
+	if !ok {
+		return createErr
+	}
+
+	d.SetId(existing.GetSlugPerm())
+
+	return resourceWebhookUpdate(d, m)
+}
+
 func resourceWebhookRead(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, "namespace")
-	repository := requiredString(d, "repository")
+	repository, resp, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		if is404(resp) {
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
 
 	req := pc.APIClient.WebhooksApi.WebhooksRead(pc.Auth, namespace, repository, d.Id())
 
@@ -231,11 +276,14 @@ func resourceWebhookRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("updated_by", webhook.GetUpdatedBy())
 	d.Set("verify_ssl", webhook.GetVerifySsl())
 
-	// namespace and repository are not returned from the entitlement read
-	// endpoint, so we can use the values stored in resource state. We rely on
-	// ForceNew to ensure if either changes a new resource is created.
+	// namespace and repository are not returned from the webhook read
+	// endpoint, so we can use the values stored in resource state. Changing
+	// namespace forces a new resource; requests are made with
+	// repository_slug_perm, so repository is kept as configured and only
+	// forces a new resource if it refers to another repository (see
+	// customizeDiffRepository).
 	d.Set("namespace", namespace)
-	d.Set("repository", repository)
+	d.Set("repository", requiredString(d, "repository"))
 
 	return nil
 }
@@ -244,7 +292,10 @@ func resourceWebhookUpdate(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, "namespace")
-	repository := requiredString(d, "repository")
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
 
 	req := pc.APIClient.WebhooksApi.WebhooksPartialUpdate(pc.Auth, namespace, repository, d.Id())
 	req = req.Data(cloudsmith.RepositoryWebhookRequestPatch{
@@ -286,10 +337,13 @@ func resourceWebhookDelete(d *schema.ResourceData, m interface{}) error {
 	pc := m.(*providerConfig)
 
 	namespace := requiredString(d, "namespace")
-	repository := requiredString(d, "repository")
+	repository, _, err := repositorySlugPerm(pc, d, namespace)
+	if err != nil {
+		return err
+	}
 
 	req := pc.APIClient.WebhooksApi.WebhooksDelete(pc.Auth, namespace, repository, d.Id())
-	_, err := pc.APIClient.WebhooksApi.WebhooksDeleteExecute(req)
+	_, err = pc.APIClient.WebhooksApi.WebhooksDeleteExecute(req)
 	if err != nil {
 		return err
 	}
@@ -319,6 +373,8 @@ func resourceWebhook() *schema.Resource {
 		Update: resourceWebhookUpdate,
 		Delete: resourceWebhookDelete,
 
+		CustomizeDiff: customizeDiffRepository("namespace"),
+
 		Importer: &schema.ResourceImporter{
 			StateContext: importWebhook,
 		},
@@ -355,6 +411,7 @@ func resourceWebhook() *schema.Resource {
 				Optional:    true,
 				Computed:    true,
 			},
+			LifecycleHint: lifecycleHintSchema(),
 			"namespace": {
 				Type:         schema.TypeString,
 				Description:  "Namespace to which this webhook belongs.",
@@ -368,15 +425,16 @@ func resourceWebhook() *schema.Resource {
 					"syntax as the standard search used for repositories, and also supports boolean " +
 					"logic operators such as OR/AND/NOT and parentheses for grouping. If a package does " +
 					"not match, the webhook will not fire.",
-				Optional: true,
+				Optional:         true,
+				ValidateDiagFunc: validatePackageQuery,
 			},
 			"repository": {
 				Type:         schema.TypeString,
-				Description:  "Repository to which this webhook belongs.",
+				Description:  "Repository to which this webhook belongs, identified by its slug or slug_perm.",
 				Required:     true,
-				ForceNew:     true,
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
+			RepositorySlugPerm: repositorySlugPermSchema(),
 			"request_body_format": {
 				Type:         schema.TypeString,
 				Description:  "The format of the payloads for webhook requests.",
@@ -444,8 +502,9 @@ func resourceWebhook() *schema.Resource {
 							ValidateFunc: validation.StringInSlice(eventTypes, false),
 						},
 						"template": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: validateWebhookTemplate,
 						},
 					},
 				},
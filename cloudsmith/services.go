@@ -0,0 +1,187 @@
+package cloudsmith
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+)
+
+// The services below wrap the parts of the generated API client used by
+// resources behind small interfaces, so that resource logic can be unit
+// tested against fakes, and so that upgrading the client touches as little
+// code as possible. Each method makes a single API request and returns the
+// raw response alongside any error, like the client itself, so that callers
+// can check for 404s with is404.
+
+// GeoIPService manages the Geo/IP rules of repositories.
+type GeoIPService interface {
+	Enable(namespace, repository string) (*http.Response, error)
+	Disable(namespace, repository string) (*http.Response, error)
+	Read(namespace, repository string) (*cloudsmith.RepositoryGeoIpRules, *http.Response, error)
+	Update(namespace, repository string, rules cloudsmith.RepositoryGeoIpRulesRequest) (*http.Response, error)
+}
+
+// SAMLService manages the SAML group syncs of organizations.
+type SAMLService interface {
+	EnableGroupSync(organization string) (*http.Response, error)
+	DisableGroupSync(organization string) (*http.Response, error)
+	GroupSyncStatus(organization string) (*cloudsmith.OrganizationGroupSyncStatus, *http.Response, error)
+	ListGroupSyncs(organization string, page, pageSize int64) ([]cloudsmith.OrganizationGroupSync, *http.Response, error)
+	CreateGroupSync(organization string, data cloudsmith.OrganizationGroupSyncRequest) (*cloudsmith.OrganizationGroupSync, *http.Response, error)
+	DeleteGroupSync(organization, slugPerm string) (*http.Response, error)
+}
+
+// ReposService looks up repositories and their privileges.
+type ReposService interface {
+	Read(namespace, repository string) (*cloudsmith.Repository, *http.Response, error)
+	List(namespace string, page, pageSize int64) ([]cloudsmith.Repository, *http.Response, error)
+	ListPrivileges(namespace, repository string, page, pageSize int64) ([]cloudsmith.RepositoryPrivilegeDict, *http.Response, error)
+}
+
+// apiGeoIPService implements GeoIPService with the API client.
+type apiGeoIPService struct {
+	auth   context.Context
+	client *cloudsmith.APIClient
+}
+
+func (s *apiGeoIPService) Enable(namespace, repository string) (*http.Response, error) {
+	req := s.client.ReposApi.ReposGeoipEnable(s.auth, namespace, repository)
+	return s.client.ReposApi.ReposGeoipEnableExecute(req)
+}
+
+func (s *apiGeoIPService) Disable(namespace, repository string) (*http.Response, error) {
+	req := s.client.ReposApi.ReposGeoipDisable(s.auth, namespace, repository)
+	return s.client.ReposApi.ReposGeoipDisableExecute(req)
+}
+
+func (s *apiGeoIPService) Read(namespace, repository string) (*cloudsmith.RepositoryGeoIpRules, *http.Response, error) {
+	req := s.client.ReposApi.ReposGeoipRead(s.auth, namespace, repository)
+	return s.client.ReposApi.ReposGeoipReadExecute(req)
+}
+
+func (s *apiGeoIPService) Update(
+	namespace, repository string, rules cloudsmith.RepositoryGeoIpRulesRequest,
+) (*http.Response, error) {
+	req := s.client.ReposApi.ReposGeoipUpdate(s.auth, namespace, repository)
+	req = req.Data(rules)
+	_, resp, err := s.client.ReposApi.ReposGeoipUpdateExecute(req)
+	return resp, err
+}
+
+// apiSAMLService implements SAMLService with the API client. Changes to the
+// group syncs of an organization are serialized, as the API rejects
+// concurrent ones.
+type apiSAMLService struct {
+	auth   context.Context
+	client *cloudsmith.APIClient
+
+	// the slug_perms of the organizations written to, keyed by the lower
+	// case slug or slug_perm they were referred to by
+	slugPerms sync.Map
+}
+
+// lockOrganization locks writes to the group syncs of an organization under
+// its slug_perm, so that writes referring to the organization by its slug and
+// by its slug_perm are serialized with each other.
+func (s *apiSAMLService) lockOrganization(organization string) func() {
+	key := strings.ToLower(organization)
+	if slugPerm, ok := s.slugPerms.Load(key); ok {
+		return lockOrganization(slugPerm.(string))
+	}
+
+	req := s.client.OrgsApi.OrgsRead(s.auth, organization)
+	org, _, err := s.client.OrgsApi.OrgsReadExecute(req)
+	if err != nil || org.GetSlugPerm() == "" {
+		// the write will fail in the same way, so there is nothing for it to
+		// be serialized with
+		return lockOrganization(organization)
+	}
+
+	s.slugPerms.Store(key, org.GetSlugPerm())
+	return lockOrganization(org.GetSlugPerm())
+}
+
+func (s *apiSAMLService) EnableGroupSync(organization string) (*http.Response, error) {
+	defer s.lockOrganization(organization)()
+
+	req := s.client.OrgsApi.OrgsSamlGroupSyncEnable(s.auth, organization)
+	return s.client.OrgsApi.OrgsSamlGroupSyncEnableExecute(req)
+}
+
+func (s *apiSAMLService) DisableGroupSync(organization string) (*http.Response, error) {
+	defer s.lockOrganization(organization)()
+
+	req := s.client.OrgsApi.OrgsSamlGroupSyncDisable(s.auth, organization)
+	return s.client.OrgsApi.OrgsSamlGroupSyncDisableExecute(req)
+}
+
+func (s *apiSAMLService) GroupSyncStatus(organization string) (*cloudsmith.OrganizationGroupSyncStatus, *http.Response, error) {
+	req := s.client.OrgsApi.OrgsSamlGroupSyncStatus(s.auth, organization)
+	return s.client.OrgsApi.OrgsSamlGroupSyncStatusExecute(req)
+}
+
+// ListGroupSyncs returns a page of group syncs; a page or page size of zero
+// leaves it to the API's default.
+func (s *apiSAMLService) ListGroupSyncs(
+	organization string, page, pageSize int64,
+) ([]cloudsmith.OrganizationGroupSync, *http.Response, error) {
+	req := s.client.OrgsApi.OrgsSamlGroupSyncList(s.auth, organization)
+	if page > 0 {
+		req = req.Page(page)
+	}
+	if pageSize > 0 {
+		req = req.PageSize(pageSize)
+	}
+	return s.client.OrgsApi.OrgsSamlGroupSyncListExecute(req)
+}
+
+func (s *apiSAMLService) CreateGroupSync(
+	organization string, data cloudsmith.OrganizationGroupSyncRequest,
+) (*cloudsmith.OrganizationGroupSync, *http.Response, error) {
+	defer s.lockOrganization(organization)()
+
+	req := s.client.OrgsApi.OrgsSamlGroupSyncCreate(s.auth, organization)
+	req = req.Data(data)
+	return s.client.OrgsApi.OrgsSamlGroupSyncCreateExecute(req)
+}
+
+func (s *apiSAMLService) DeleteGroupSync(organization, slugPerm string) (*http.Response, error) {
+	defer s.lockOrganization(organization)()
+
+	req := s.client.OrgsApi.OrgsSamlGroupSyncDelete(s.auth, organization, slugPerm)
+	return s.client.OrgsApi.OrgsSamlGroupSyncDeleteExecute(req)
+}
+
+// apiReposService implements ReposService with the API client.
+type apiReposService struct {
+	auth   context.Context
+	client *cloudsmith.APIClient
+}
+
+func (s *apiReposService) Read(namespace, repository string) (*cloudsmith.Repository, *http.Response, error) {
+	req := s.client.ReposApi.ReposRead(s.auth, namespace, repository)
+	return s.client.ReposApi.ReposReadExecute(req)
+}
+
+func (s *apiReposService) List(namespace string, page, pageSize int64) ([]cloudsmith.Repository, *http.Response, error) {
+	req := s.client.ReposApi.ReposNamespaceList(s.auth, namespace)
+	req = req.Page(page)
+	req = req.PageSize(pageSize)
+	return s.client.ReposApi.ReposNamespaceListExecute(req)
+}
+
+func (s *apiReposService) ListPrivileges(
+	namespace, repository string, page, pageSize int64,
+) ([]cloudsmith.RepositoryPrivilegeDict, *http.Response, error) {
+	req := s.client.ReposApi.ReposPrivilegesList(s.auth, namespace, repository)
+	req = req.Page(page)
+	req = req.PageSize(pageSize)
+	privileges, resp, err := s.client.ReposApi.ReposPrivilegesListExecute(req)
+	if err != nil {
+		return nil, resp, err
+	}
+	return privileges.GetPrivileges(), resp, nil
+}
@@ -0,0 +1,459 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cloudsmith-io/cloudsmith-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/samber/lo"
+)
+
+var errFakeNotFound = errors.New("404 Not Found")
+
+// notFound returns the response and error of the API client for a 404.
+func notFound() (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusNotFound}, errFakeNotFound
+}
+
+// fakeGeoIPService is an in-memory GeoIPService, in which the rules of a
+// repository can only be read and updated once Geo/IP has been enabled.
+type fakeGeoIPService struct {
+	rules map[string]*cloudsmith.RepositoryGeoIpRules
+}
+
+func newFakeGeoIPService() *fakeGeoIPService {
+	return &fakeGeoIPService{rules: map[string]*cloudsmith.RepositoryGeoIpRules{}}
+}
+
+func (s *fakeGeoIPService) Enable(namespace, repository string) (*http.Response, error) {
+	if s.rules[namespace+"/"+repository] == nil {
+		s.rules[namespace+"/"+repository] = &cloudsmith.RepositoryGeoIpRules{}
+	}
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func (s *fakeGeoIPService) Disable(namespace, repository string) (*http.Response, error) {
+	if s.rules[namespace+"/"+repository] == nil {
+		return notFound()
+	}
+	delete(s.rules, namespace+"/"+repository)
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func (s *fakeGeoIPService) Read(namespace, repository string) (*cloudsmith.RepositoryGeoIpRules, *http.Response, error) {
+	rules := s.rules[namespace+"/"+repository]
+	if rules == nil {
+		resp, err := notFound()
+		return nil, resp, err
+	}
+	return rules, &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func (s *fakeGeoIPService) Update(
+	namespace, repository string, rules cloudsmith.RepositoryGeoIpRulesRequest,
+) (*http.Response, error) {
+	if s.rules[namespace+"/"+repository] == nil {
+		return notFound()
+	}
+	s.rules[namespace+"/"+repository] = &cloudsmith.RepositoryGeoIpRules{
+		Cidr:        rules.Cidr,
+		CountryCode: rules.CountryCode,
+	}
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+// fakeReposService is an in-memory ReposService, whose privileges are keyed
+// by "<namespace>/<repository slug>".
+type fakeReposService struct {
+	repositories map[string][]cloudsmith.Repository
+	privileges   map[string][]cloudsmith.RepositoryPrivilegeDict
+}
+
+func (s *fakeReposService) Read(namespace, repository string) (*cloudsmith.Repository, *http.Response, error) {
+	for _, r := range s.repositories[namespace] {
+		if r.GetSlug() == repository || r.GetSlugPerm() == repository {
+			return &r, &http.Response{StatusCode: http.StatusOK}, nil
+		}
+	}
+	resp, err := notFound()
+	return nil, resp, err
+}
+
+func (s *fakeReposService) List(namespace string, page, pageSize int64) ([]cloudsmith.Repository, *http.Response, error) {
+	header := http.Header{}
+	header.Set("X-Pagination-Pagetotal", "1")
+	if page > 1 {
+		return nil, &http.Response{StatusCode: http.StatusOK, Header: header}, nil
+	}
+	return s.repositories[namespace], &http.Response{StatusCode: http.StatusOK, Header: header}, nil
+}
+
+func (s *fakeReposService) ListPrivileges(
+	namespace, repository string, page, pageSize int64,
+) ([]cloudsmith.RepositoryPrivilegeDict, *http.Response, error) {
+	r, resp, err := s.Read(namespace, repository)
+	if err != nil {
+		return nil, resp, err
+	}
+	privileges := s.privileges[namespace+"/"+r.GetSlug()]
+	start := lo.Clamp((page-1)*pageSize, 0, int64(len(privileges)))
+	end := lo.Clamp(page*pageSize, 0, int64(len(privileges)))
+	return privileges[start:end], &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+// fakeSAMLService is an in-memory SAMLService with group sync enabled, which
+// rejects group syncs duplicating the idp_key, idp_value and team of another.
+type fakeSAMLService struct {
+	syncs []cloudsmith.OrganizationGroupSync
+
+	// if set, the only organization identifier the fake knows, e.g. the
+	// slug_perm of an organization whose slug has been renamed
+	organization string
+
+	// whether DisableGroupSync has been called
+	disabled bool
+}
+
+func (s *fakeSAMLService) knows(organization string) bool {
+	return s.organization == "" || s.organization == organization
+}
+
+func (s *fakeSAMLService) EnableGroupSync(organization string) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func (s *fakeSAMLService) DisableGroupSync(organization string) (*http.Response, error) {
+	s.disabled = true
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func (s *fakeSAMLService) GroupSyncStatus(organization string) (*cloudsmith.OrganizationGroupSyncStatus, *http.Response, error) {
+	return &cloudsmith.OrganizationGroupSyncStatus{}, &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func (s *fakeSAMLService) ListGroupSyncs(
+	organization string, page, pageSize int64,
+) ([]cloudsmith.OrganizationGroupSync, *http.Response, error) {
+	if !s.knows(organization) {
+		resp, err := notFound()
+		return nil, resp, err
+	}
+	header := http.Header{}
+	header.Set("X-Pagination-Pagetotal", "1")
+	if page > 1 {
+		return nil, &http.Response{StatusCode: http.StatusOK, Header: header}, nil
+	}
+	return s.syncs, &http.Response{StatusCode: http.StatusOK, Header: header}, nil
+}
+
+func (s *fakeSAMLService) CreateGroupSync(
+	organization string, data cloudsmith.OrganizationGroupSyncRequest,
+) (*cloudsmith.OrganizationGroupSync, *http.Response, error) {
+	for _, sync := range s.syncs {
+		if sync.IdpKey == data.IdpKey && sync.IdpValue == data.IdpValue && sync.Team == data.Team {
+			return nil, &http.Response{StatusCode: http.StatusBadRequest}, errors.New("400 Bad Request")
+		}
+	}
+	sync := cloudsmith.OrganizationGroupSync{
+		IdpKey:   data.IdpKey,
+		IdpValue: data.IdpValue,
+		Role:     data.Role,
+		SlugPerm: cloudsmith.PtrString(fmt.Sprintf("sync-%d", len(s.syncs))),
+		Team:     data.Team,
+	}
+	s.syncs = append(s.syncs, sync)
+	return &sync, &http.Response{StatusCode: http.StatusCreated}, nil
+}
+
+func (s *fakeSAMLService) DeleteGroupSync(organization, slugPerm string) (*http.Response, error) {
+	if !s.knows(organization) {
+		return notFound()
+	}
+	for i, sync := range s.syncs {
+		if sync.GetSlugPerm() == slugPerm {
+			s.syncs = append(s.syncs[:i], s.syncs[i+1:]...)
+			return &http.Response{StatusCode: http.StatusNoContent}, nil
+		}
+	}
+	return notFound()
+}
+
+// TestRepositoryGeoIpRulesLifecycle creates, reads and deletes Geo/IP rules
+// against fake services, verifying what is sent to the API and stored in
+// state without making any API requests.
+func TestRepositoryGeoIpRulesLifecycle(t *testing.T) {
+	t.Parallel()
+
+	geoIP := newFakeGeoIPService()
+	pc := &providerConfig{
+		GeoIP: geoIP,
+		Repos: &fakeReposService{repositories: map[string][]cloudsmith.Repository{
+			"my-org": {{Slug: cloudsmith.PtrString("my-repo"), SlugPerm: cloudsmith.PtrString("AbCdEfGhIjKl")}},
+		}},
+	}
+
+	r := resourceRepositoryGeoIpRules()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		Namespace:               "my-org",
+		Repository:              "my-repo",
+		CidrAllow:               []interface{}{"2001:DB8::/32", "10.0.0.0/24"},
+		CountryCodeDeny:         []interface{}{"gb"},
+		WaitForConsistency:      false,
+		DisableFeatureOnDestroy: true,
+	})
+
+	if err := resourceRepositoryGeoIpRulesCreate(d, pc); err != nil {
+		t.Fatalf("unexpected error creating: %s", err)
+	}
+
+	if d.Id() != "my-org.my-repo" {
+		t.Errorf("expected ID my-org.my-repo, got %q", d.Id())
+	}
+	if got := d.Get(RepositorySlugPerm); got != "AbCdEfGhIjKl" {
+		t.Errorf("expected the repository slug_perm to be stored, got %q", got)
+	}
+
+	rules := geoIP.rules["my-org/AbCdEfGhIjKl"]
+	if rules == nil {
+		t.Fatal("expected Geo/IP to be enabled for the repository")
+	}
+	cidr := rules.GetCidr()
+	gotAllow := cidr.GetAllow()
+	sort.Strings(gotAllow)
+	if want := []string{"10.0.0.0/24", "2001:db8::/32"}; !reflect.DeepEqual(gotAllow, want) {
+		t.Errorf("expected normalized CIDRs %v to be sent, got %v", want, gotAllow)
+	}
+	countryCode := rules.GetCountryCode()
+	if want := []string{"GB"}; !reflect.DeepEqual(countryCode.GetDeny(), want) {
+		t.Errorf("expected normalized country codes %v to be sent, got %v", want, countryCode.GetDeny())
+	}
+
+	if err := resourceRepositoryGeoIpRulesDelete(d, pc); err != nil {
+		t.Fatalf("unexpected error deleting: %s", err)
+	}
+	if geoIP.rules["my-org/AbCdEfGhIjKl"] != nil {
+		t.Fatal("expected Geo/IP to be disabled for the repository")
+	}
+
+	// once disabled the rules are gone, so the resource is removed from state
+	if err := resourceRepositoryGeoIpRulesRead(d, pc); err != nil {
+		t.Fatalf("unexpected error reading: %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the resource to be removed from state, got ID %q", d.Id())
+	}
+}
+
+// flakyGeoIPService is a fakeGeoIPService whose Enable fails with the given
+// status a number of times, and then rejects repositories on which Geo/IP is
+// already enabled with enabledStatus, or a 400 as the API usually does.
+type flakyGeoIPService struct {
+	*fakeGeoIPService
+	failures      int
+	status        int
+	enabledStatus int
+}
+
+func (s *flakyGeoIPService) Enable(namespace, repository string) (*http.Response, error) {
+	if s.failures > 0 {
+		s.failures--
+		return &http.Response{StatusCode: s.status}, fmt.Errorf("%d", s.status)
+	}
+	if s.rules[namespace+"/"+repository] != nil {
+		if s.enabledStatus != 0 {
+			return &http.Response{StatusCode: s.enabledStatus}, fmt.Errorf("%d", s.enabledStatus)
+		}
+		return &http.Response{StatusCode: http.StatusBadRequest}, errors.New("400 Bad Request")
+	}
+	return s.fakeGeoIPService.Enable(namespace, repository)
+}
+
+func TestEnableGeoIpRules(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		alreadyEnabled bool
+		enabledStatus  int
+		failures       int
+		status         int
+		wantErr        bool
+	}{
+		{"enabled", false, 0, 0, 0, false},
+		{"already enabled", true, 0, 0, 0, false},
+		{"already enabled conflict", true, http.StatusConflict, 0, 0, false},
+		{"transient failures", false, 0, 2, http.StatusInternalServerError, false},
+		{"conflict", false, 0, 1, http.StatusConflict, false},
+		{"forbidden", false, 0, 1, http.StatusForbidden, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			geoIP := &flakyGeoIPService{
+				fakeGeoIPService: newFakeGeoIPService(),
+				failures:         tc.failures,
+				status:           tc.status,
+				enabledStatus:    tc.enabledStatus,
+			}
+			if tc.alreadyEnabled {
+				geoIP.rules["my-org/my-repo"] = &cloudsmith.RepositoryGeoIpRules{}
+			}
+			pc := &providerConfig{GeoIP: geoIP}
+
+			err := enableGeoIpRules(pc, "my-org", "my-repo", time.Millisecond)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %t, got: %v", tc.wantErr, err)
+			}
+			if !tc.wantErr && geoIP.rules["my-org/my-repo"] == nil {
+				t.Error("expected Geo/IP to be enabled for the repository")
+			}
+		})
+	}
+}
+
+// failingUpdateGeoIPService is a fakeGeoIPService whose rules can't be
+// updated.
+type failingUpdateGeoIPService struct {
+	*fakeGeoIPService
+}
+
+func (s *failingUpdateGeoIPService) Update(
+	namespace, repository string, rules cloudsmith.RepositoryGeoIpRulesRequest,
+) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusBadRequest}, errors.New("400 Bad Request")
+}
+
+// TestRepositoryGeoIpRulesCreatePartial verifies that Geo/IP rules whose
+// create fails after Geo/IP has been enabled are still tracked in state, so
+// that they are cleaned up rather than orphaned.
+func TestRepositoryGeoIpRulesCreatePartial(t *testing.T) {
+	t.Parallel()
+
+	geoIP := &failingUpdateGeoIPService{fakeGeoIPService: newFakeGeoIPService()}
+	pc := &providerConfig{
+		GeoIP: geoIP,
+		Repos: &fakeReposService{repositories: map[string][]cloudsmith.Repository{
+			"my-org": {{Slug: cloudsmith.PtrString("my-repo"), SlugPerm: cloudsmith.PtrString("AbCdEfGhIjKl")}},
+		}},
+	}
+
+	r := resourceRepositoryGeoIpRules()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		Namespace:  "my-org",
+		Repository: "my-repo",
+		CidrAllow:  []interface{}{"10.0.0.0/24"},
+	})
+
+	if err := resourceRepositoryGeoIpRulesCreate(d, pc); err == nil {
+		t.Fatal("expected an error creating")
+	}
+	if geoIP.rules["my-org/AbCdEfGhIjKl"] == nil {
+		t.Fatal("expected Geo/IP to be enabled for the repository")
+	}
+	if d.Id() != "my-org.my-repo" {
+		t.Errorf("expected the resource to be tracked with ID my-org.my-repo, got %q", d.Id())
+	}
+}
+
+// TestRepositoryGeoIpRulesDeleteKeepsGeoIpEnabled verifies that destroying
+// Geo/IP rules only clears them unless disable_feature_on_destroy is set, so
+// that upgrading doesn't start switching Geo/IP off for repositories.
+func TestRepositoryGeoIpRulesDeleteKeepsGeoIpEnabled(t *testing.T) {
+	t.Parallel()
+
+	geoIP := newFakeGeoIPService()
+	pc := &providerConfig{
+		GeoIP: geoIP,
+		Repos: &fakeReposService{repositories: map[string][]cloudsmith.Repository{
+			"my-org": {{Slug: cloudsmith.PtrString("my-repo"), SlugPerm: cloudsmith.PtrString("AbCdEfGhIjKl")}},
+		}},
+	}
+
+	r := resourceRepositoryGeoIpRules()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		Namespace:          "my-org",
+		Repository:         "my-repo",
+		CidrAllow:          []interface{}{"10.0.0.0/24"},
+		WaitForConsistency: false,
+	})
+
+	if err := resourceRepositoryGeoIpRulesCreate(d, pc); err != nil {
+		t.Fatalf("unexpected error creating: %s", err)
+	}
+	if err := resourceRepositoryGeoIpRulesDelete(d, pc); err != nil {
+		t.Fatalf("unexpected error deleting: %s", err)
+	}
+
+	rules := geoIP.rules["my-org/AbCdEfGhIjKl"]
+	if rules == nil {
+		t.Fatal("expected Geo/IP to stay enabled for the repository")
+	}
+	cidr := rules.GetCidr()
+	if len(cidr.GetAllow()) != 0 {
+		t.Errorf("expected the rules to be cleared, got %v", cidr.GetAllow())
+	}
+}
+
+// TestSAMLGroupSyncsDeleteDisablesGroupSync verifies that destroying a
+// cloudsmith_saml_group_syncs resource with disable_feature_on_destroy only
+// disables group sync for the organization once no group syncs remain.
+func TestSAMLGroupSyncsDeleteDisablesGroupSync(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		others   []cloudsmith.OrganizationGroupSync
+		disabled bool
+	}{
+		{"no other group syncs", nil, true},
+		{"other group syncs", []cloudsmith.OrganizationGroupSync{{
+			IdpKey:   "department",
+			IdpValue: "engineering",
+			SlugPerm: cloudsmith.PtrString("other-sync"),
+			Team:     "eng",
+		}}, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			saml := &fakeSAMLService{syncs: tc.others}
+			pc := &providerConfig{SAML: saml}
+
+			d := schema.TestResourceDataRaw(t, resourceSAMLGroupSyncs().Schema, map[string]interface{}{
+				"organization": "my-org",
+				"idp_key":      "groups",
+				"mapping": []interface{}{
+					map[string]interface{}{"idp_value": "developers", "team": "dev", "role": "Member"},
+				},
+				DisableFeatureOnDestroy: true,
+			})
+			if diags := samlGroupSyncsReconcile(context.Background(), d, pc); diags.HasError() {
+				t.Fatalf("unexpected error creating: %v", diags)
+			}
+
+			if err := samlGroupSyncsDelete(d, pc); err != nil {
+				t.Fatalf("unexpected error deleting: %s", err)
+			}
+			if saml.disabled != tc.disabled {
+				t.Errorf("expected group sync to be disabled: %t, got %t", tc.disabled, saml.disabled)
+			}
+			if len(saml.syncs) != len(tc.others) {
+				t.Errorf("expected only the other group syncs to remain, got %v", saml.syncs)
+			}
+		})
+	}
+}
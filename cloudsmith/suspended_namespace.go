@@ -0,0 +1,60 @@
+package cloudsmith
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// suspendedNamespaceStatus is the HTTP status line the API responds with to
+// requests against a suspended (or otherwise lapsed) namespace. API errors
+// carry the status line in their message, so it survives being wrapped.
+var suspendedNamespaceStatus = fmt.Sprintf("%d %s", http.StatusPaymentRequired, http.StatusText(http.StatusPaymentRequired))
+
+// isSuspendedNamespaceDiagnostic reports whether a diagnostic is the result of
+// the namespace a resource belongs to being suspended.
+func isSuspendedNamespaceDiagnostic(d diag.Diagnostic) bool {
+	return d.Severity == diag.Error && strings.Contains(d.Summary, suspendedNamespaceStatus)
+}
+
+// tolerateSuspendedNamespaces wraps the read function of a resource so that,
+// unless the provider is configured with error_on_suspended_namespace, errors
+// caused by its namespace being suspended are downgraded to warnings. The
+// resource is then left in state as it was, rather than every resource in
+// the namespace failing to refresh.
+func tolerateSuspendedNamespaces(r *schema.Resource) {
+	read := r.ReadContext
+	if read == nil {
+		legacyRead := r.Read
+		read = func(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			return diag.FromErr(legacyRead(d, m))
+		}
+		r.Read = nil
+	}
+
+	r.ReadContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		diags := read(ctx, d, m)
+
+		pc := m.(*providerConfig)
+		if pc.ErrorOnSuspendedNamespace {
+			return diags
+		}
+
+		for i := range diags {
+			if isSuspendedNamespaceDiagnostic(diags[i]) {
+				diags[i].Severity = diag.Warning
+				diags[i].Detail = fmt.Sprintf(
+					"Resource %s could not be refreshed because its namespace appears to be suspended, "+
+						"so the existing state has been kept.",
+					d.Id(),
+				)
+			}
+		}
+
+		return diags
+	}
+}
@@ -0,0 +1,65 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// TestTolerateSuspendedNamespaces verifies that only errors caused by a
+// suspended namespace are downgraded to warnings, and only when
+// error_on_suspended_namespace is disabled.
+func TestTolerateSuspendedNamespaces(t *testing.T) {
+	t.Parallel()
+
+	suspendedErr := fmt.Errorf("error reading repository: %w", errors.New("402 Payment Required (Namespace is suspended.)"))
+	otherErr := errors.New("500 Internal Server Error")
+
+	testCases := []struct {
+		name                      string
+		errorOnSuspendedNamespace bool
+		err                       error
+		wantSeverity              diag.Severity
+	}{
+		{"suspended", false, suspendedErr, diag.Warning},
+		{"suspended with error enabled", true, suspendedErr, diag.Error},
+		{"other error", false, otherErr, diag.Error},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &schema.Resource{
+				Read: func(d *schema.ResourceData, m interface{}) error {
+					return tc.err
+				},
+				Schema: map[string]*schema.Schema{},
+			}
+			tolerateSuspendedNamespaces(r)
+
+			if r.Read != nil {
+				t.Fatal("expected Read to be replaced by ReadContext")
+			}
+
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+			d.SetId("test")
+
+			pc := &providerConfig{ErrorOnSuspendedNamespace: tc.errorOnSuspendedNamespace}
+			diags := r.ReadContext(context.Background(), d, pc)
+
+			if len(diags) != 1 || diags[0].Severity != tc.wantSeverity {
+				t.Fatalf("expected a single diagnostic with severity %v, got: %v", tc.wantSeverity, diags)
+			}
+			if d.Id() != "test" {
+				t.Fatalf("expected resource to be kept in state, got id %q", d.Id())
+			}
+		})
+	}
+}
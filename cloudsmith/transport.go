@@ -0,0 +1,304 @@
+package cloudsmith
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// metricsSummaryInterval controls how often (in number of requests) an
+// aggregate summary of API usage is logged.
+const metricsSummaryInterval = 100
+
+// metricsTransport wraps an http.RoundTripper and records the endpoint,
+// duration, status and number of retries of every API call made by the
+// provider, logging each call at DEBUG level and aggregate counts at INFO
+// level, periodically and once more when the provider stops. It wraps the
+// retryTransport, so that each call is recorded once however many attempts it
+// took.
+//
+// API requests are made using the provider's long-lived auth context rather
+// than the per-operation context handed to resources by the SDK, so the
+// context holding the SDK's logger is captured at configure time and used for
+// all logging.
+type metricsTransport struct {
+	logCtx    context.Context
+	transport http.RoundTripper
+	rateLimit *rateLimitBudget
+
+	mu       sync.Mutex
+	requests int64
+	retries  int64
+	failures int64
+	duration time.Duration
+	statuses map[int]int64
+
+	finalSummary sync.Once
+}
+
+// metricsTransports holds every metricsTransport created by the provider, so
+// that their final summaries can be logged when the provider stops.
+var metricsTransports = struct {
+	sync.Mutex
+	all []*metricsTransport
+}{}
+
+func newMetricsTransport(logCtx context.Context, transport http.RoundTripper, rateLimit *rateLimitBudget) *metricsTransport {
+	t := &metricsTransport{
+		logCtx:    logCtx,
+		transport: transport,
+		rateLimit: rateLimit,
+		statuses:  map[int]int64{},
+	}
+
+	metricsTransports.Lock()
+	metricsTransports.all = append(metricsTransports.all, t)
+	metricsTransports.Unlock()
+
+	return t
+}
+
+// LogAPIUsageSummaries logs the final summary of API usage of the provider. It
+// is called once the plugin has been shut down by Terraform at the end of a
+// run, so that the summary is logged even if fewer than
+// metricsSummaryInterval requests were made.
+func LogAPIUsageSummaries() {
+	metricsTransports.Lock()
+	defer metricsTransports.Unlock()
+
+	for _, t := range metricsTransports.all {
+		t.logFinalSummary()
+	}
+}
+
+// retryCounterKey is the context key of the number of times a request has
+// been retried, which the retryTransport increments for the metricsTransport.
+type retryCounterKey struct{}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retries := new(int64)
+	req = req.WithContext(context.WithValue(req.Context(), retryCounterKey{}, retries))
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+		t.rateLimit.observe(resp.Header)
+	}
+
+	tflog.Debug(t.logCtx, "Cloudsmith API request", map[string]interface{}{
+		"endpoint":    req.URL.Path,
+		"method":      req.Method,
+		"status":      status,
+		"retries":     *retries,
+		"duration_ms": elapsed.Milliseconds(),
+	})
+
+	t.record(status, *retries, elapsed, err != nil)
+
+	return resp, err
+}
+
+// record updates the aggregate counters for a completed request, logging a
+// summary every metricsSummaryInterval requests.
+func (t *metricsTransport) record(status int, retries int64, elapsed time.Duration, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.requests++
+	t.retries += retries
+	t.duration += elapsed
+	if failed {
+		t.failures++
+	} else {
+		t.statuses[status]++
+	}
+
+	if t.requests%metricsSummaryInterval == 0 {
+		tflog.Info(t.logCtx, "Cloudsmith API usage summary", t.summaryFields())
+	}
+}
+
+// logFinalSummary logs the aggregate counters once, when the provider stops.
+func (t *metricsTransport) logFinalSummary() {
+	t.finalSummary.Do(func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.requests > 0 {
+			tflog.Info(t.logCtx, "Cloudsmith API usage final summary", t.summaryFields())
+		}
+	})
+}
+
+// logFinalSummaryOnStop logs the final summary when stopCtx is cancelled,
+// i.e. when Terraform stops the provider because the run was interrupted.
+func (t *metricsTransport) logFinalSummaryOnStop(stopCtx context.Context) {
+	go func() {
+		<-stopCtx.Done()
+		t.logFinalSummary()
+	}()
+}
+
+// summaryFields returns the aggregate counters as tflog fields. Callers must
+// hold t.mu.
+func (t *metricsTransport) summaryFields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"requests":          t.requests,
+		"retries":           t.retries,
+		"failures":          t.failures,
+		"total_duration_ms": t.duration.Milliseconds(),
+	}
+	for status, count := range t.statuses {
+		fields[fmt.Sprintf("status_%d", status)] = count
+	}
+	if rateLimit, ok := t.rateLimit.summary(); ok {
+		fields["rate_limit"] = rateLimit
+	}
+	return fields
+}
+
+const (
+	retryMaxAttempts = 4
+	retryMinBackoff  = time.Second
+	retryMaxBackoff  = time.Second * 30
+)
+
+// defaultRetryableStatusCodes are the responses which indicate a transient
+// failure (rate limiting or an unavailable backend), after which the request
+// is retried.
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// idempotentMethods are the HTTP methods whose requests can be repeated
+// without changing their effect, which are retried on any retryable status.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// retryTransport wraps an http.RoundTripper and retries requests which fail
+// with a retryable status code, backing off exponentially (or as requested by
+// a Retry-After header) between attempts. Requests whose body can't be
+// replayed are never retried. Other requests, such as POSTs creating objects,
+// may already have been processed when a gateway fails, so they are only
+// retried when rate limited, which guarantees they weren't.
+type retryTransport struct {
+	logCtx    context.Context
+	transport http.RoundTripper
+
+	retryableStatusCodes map[int]bool
+	maxAttempts          int
+	minBackoff           time.Duration
+	maxBackoff           time.Duration
+}
+
+// newRetryTransport returns a retryTransport retrying the default retryable
+// status codes as well as any additional ones, e.g. the nonstandard codes used
+// by some gateways.
+func newRetryTransport(logCtx context.Context, transport http.RoundTripper, additionalStatusCodes []int) *retryTransport {
+	retryableStatusCodes := map[int]bool{}
+	for _, code := range defaultRetryableStatusCodes {
+		retryableStatusCodes[code] = true
+	}
+	for _, code := range additionalStatusCodes {
+		retryableStatusCodes[code] = true
+	}
+
+	return &retryTransport{
+		logCtx:               logCtx,
+		transport:            transport,
+		retryableStatusCodes: retryableStatusCodes,
+		maxAttempts:          retryMaxAttempts,
+		minBackoff:           retryMinBackoff,
+		maxBackoff:           retryMaxBackoff,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil || !t.retryable(req, resp) || attempt >= t.maxAttempts {
+			return resp, err
+		}
+
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, nil
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		if retries, ok := req.Context().Value(retryCounterKey{}).(*int64); ok {
+			*retries++
+		}
+
+		backoff := t.backoff(attempt, resp)
+
+		// the connection can only be reused once the body has been read
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		tflog.Debug(t.logCtx, "Retrying Cloudsmith API request", map[string]interface{}{
+			"endpoint":   req.URL.Path,
+			"method":     req.Method,
+			"status":     resp.StatusCode,
+			"attempt":    attempt,
+			"backoff_ms": backoff.Milliseconds(),
+		})
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable returns whether a request can be retried after the response.
+func (t *retryTransport) retryable(req *http.Request, resp *http.Response) bool {
+	if !t.retryableStatusCodes[resp.StatusCode] {
+		return false
+	}
+	return idempotentMethods[req.Method] || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// backoff returns how long to wait before the next attempt: the delay given
+// by the response's Retry-After header if it has one, otherwise an
+// exponentially increasing delay, in either case capped at maxBackoff.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	backoff := t.minBackoff << (attempt - 1)
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		backoff = time.Duration(seconds) * time.Second
+	}
+
+	if backoff > t.maxBackoff || backoff < 0 {
+		backoff = t.maxBackoff
+	}
+
+	return backoff
+}
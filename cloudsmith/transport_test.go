@@ -0,0 +1,189 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMetricsTransport_recordsRequests(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := newMetricsTransport(context.Background(), http.DefaultTransport, &rateLimitBudget{})
+	client := &http.Client{Transport: transport}
+
+	for _, path := range []string{"/found/", "/found/", "/missing/"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		resp.Body.Close()
+	}
+
+	fields := transport.summaryFields()
+	if fields["requests"] != int64(3) {
+		t.Errorf("expected 3 requests, got %v", fields["requests"])
+	}
+	if fields["status_200"] != int64(2) {
+		t.Errorf("expected 2 successful requests, got %v", fields["status_200"])
+	}
+	if fields["status_404"] != int64(1) {
+		t.Errorf("expected 1 not found request, got %v", fields["status_404"])
+	}
+	if fields["failures"] != int64(0) {
+		t.Errorf("expected no failed requests, got %v", fields["failures"])
+	}
+}
+
+// TestMetricsTransport_recordsRetries verifies that a request retried by the
+// retryTransport it wraps is recorded once, with its number of retries.
+func TestMetricsTransport_recordsRetries(t *testing.T) {
+	t.Parallel()
+
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	retry := newRetryTransport(context.Background(), http.DefaultTransport, nil)
+	retry.minBackoff = time.Millisecond
+	transport := newMetricsTransport(context.Background(), retry, &rateLimitBudget{})
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	fields := transport.summaryFields()
+	if fields["requests"] != int64(1) {
+		t.Errorf("expected 1 request, got %v", fields["requests"])
+	}
+	if fields["retries"] != int64(2) {
+		t.Errorf("expected 2 retries, got %v", fields["retries"])
+	}
+	if fields["status_200"] != int64(1) {
+		t.Errorf("expected 1 successful request, got %v", fields["status_200"])
+	}
+}
+
+func TestRetryTransport_retriesRetryableStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		method           string
+		status           int
+		additional       []int
+		expectedAttempts int32
+		expectedStatus   int
+	}{
+		{"default retryable status", http.MethodPut, http.StatusServiceUnavailable, nil, 3, http.StatusOK},
+		{"additional retryable status", http.MethodPut, 499, []int{498, 499}, 3, http.StatusOK},
+		{"non-retryable status", http.MethodPut, 499, nil, 1, 499},
+		{"attempts exhausted", http.MethodPut, http.StatusTooManyRequests, nil, 4, http.StatusTooManyRequests},
+		{"non-idempotent method", http.MethodPost, http.StatusBadGateway, nil, 1, http.StatusBadGateway},
+		{"non-idempotent method rate limited", http.MethodPost, http.StatusTooManyRequests, nil, 3, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// request bodies must be replayed on every attempt
+				if body, _ := io.ReadAll(r.Body); string(body) != "payload" {
+					t.Errorf("expected body %q, got %q", "payload", body)
+				}
+
+				// the attempts exhausted case never succeeds
+				if atomic.AddInt32(&attempts, 1) < 3 || tc.expectedAttempts > 3 {
+					w.WriteHeader(tc.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			transport := newRetryTransport(context.Background(), http.DefaultTransport, tc.additional)
+			transport.minBackoff = time.Millisecond
+			client := &http.Client{Transport: transport}
+
+			req, err := http.NewRequest(tc.method, server.URL, strings.NewReader("payload"))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+			if attempts != tc.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", tc.expectedAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_backoff(t *testing.T) {
+	t.Parallel()
+
+	transport := newRetryTransport(context.Background(), http.DefaultTransport, nil)
+
+	testCases := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		expected   time.Duration
+	}{
+		{"first attempt", 1, "", time.Second},
+		{"third attempt", 3, "", time.Second * 4},
+		{"capped", 10, "", retryMaxBackoff},
+		{"retry after", 1, "7", time.Second * 7},
+		{"retry after capped", 1, "3600", retryMaxBackoff},
+		{"invalid retry after", 2, "soon", time.Second * 2},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &http.Response{Header: http.Header{}}
+			if tc.retryAfter != "" {
+				resp.Header.Set("Retry-After", tc.retryAfter)
+			}
+
+			if backoff := transport.backoff(tc.attempt, resp); backoff != tc.expected {
+				t.Errorf("expected backoff %s, got %s", tc.expected, backoff)
+			}
+		})
+	}
+}
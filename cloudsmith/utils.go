@@ -2,12 +2,12 @@ package cloudsmith
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
 	"time"
 
-	"github.com/samber/lo"
-
 	"github.com/cloudsmith-io/cloudsmith-api-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -22,6 +22,8 @@ var (
 	defaultDeletionInterval = time.Second * 10
 	defaultUpdateTimeout    = time.Minute * 1
 	defaultUpdateInterval   = time.Second * 2
+
+	defaultPageSize int64 = 100
 )
 
 // contains returns true if value equals any element in the slice.
@@ -35,22 +37,109 @@ func contains[T comparable](slice []T, value T) bool {
 }
 
 // expandStrings retrieves a *schema.Set from TF state and converts it to
-// a slice of strings which we can use with the API bindings.
+// a slice of strings which we can use with the API bindings. The strings are
+// sorted, so that the same set always results in the same request.
 func expandStrings(d *schema.ResourceData, key string) []string {
-	set := d.Get(key).(*schema.Set)
-	return lo.Map(set.List(), func(item interface{}, _ int) string {
-		return item.(string)
-	})
+	list := d.Get(key).(*schema.Set).List()
+
+	strings := make([]string, len(list))
+	for i, item := range list {
+		strings[i] = item.(string)
+	}
+	sort.Strings(strings)
+
+	return strings
 }
 
 // flattenStrings converts a slice of strings such as might be returned by the
 // API bindings to a *schema.Set which can be stored in TF state.
 func flattenStrings(strings []string) *schema.Set {
-	set := schema.NewSet(schema.HashString, []interface{}{})
-	for _, s := range strings {
-		set.Add(s)
+	return flattenStringSet(strings, schema.HashString)
+}
+
+// flattenStringSet converts a slice of strings to a *schema.Set using the
+// given hash function, which must be the Set function of the attribute it is
+// stored in. Storing a *schema.Set is much cheaper than storing a slice, which
+// the SDK converts to a set one element at a time.
+func flattenStringSet(strings []string, hash schema.SchemaSetFunc) *schema.Set {
+	items := make([]interface{}, len(strings))
+	for i, s := range strings {
+		items[i] = s
+	}
+
+	return schema.NewSet(hash, items)
+}
+
+// EnsureFeatureEnabled is the attribute controlling whether a resource enables
+// the feature it configures when created.
+const EnsureFeatureEnabled string = "ensure_feature_enabled"
+
+// DisableFeatureOnDestroy is the attribute controlling whether a resource
+// disables the feature it configures when destroyed. It is separate from
+// EnsureFeatureEnabled, and off by default, because disabling a feature also
+// affects whatever else relies on it.
+const DisableFeatureOnDestroy string = "disable_feature_on_destroy"
+
+// ensureFeatureEnabledSchema returns the schema of the EnsureFeatureEnabled
+// attribute for a resource configuring the given feature.
+func ensureFeatureEnabledSchema(feature string, defaultValue bool) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Description: fmt.Sprintf("If enabled, %s is enabled when the resource is created.", feature),
+		Optional:    true,
+		Default:     defaultValue,
+	}
+}
+
+// disableFeatureOnDestroySchema returns the schema of the
+// DisableFeatureOnDestroy attribute for a resource configuring the given
+// feature.
+func disableFeatureOnDestroySchema(feature string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Description: fmt.Sprintf("If enabled, %s is disabled when the resource is destroyed.", feature),
+		Optional:    true,
+		Default:     false,
+	}
+}
+
+// hashNormalizedString returns a set hash function for strings which hashes
+// each value after normalizing it, so that values which only differ in ways
+// the API doesn't care about (e.g. case) are treated as the same element.
+func hashNormalizedString(normalize func(string) string) schema.SchemaSetFunc {
+	return func(v interface{}) int {
+		return schema.HashString(normalize(v.(string)))
+	}
+}
+
+// pageFetchFunc retrieves a single page of results from a paginated list
+// endpoint.
+type pageFetchFunc[T any] func(page, pageSize int64) ([]T, *http.Response, error)
+
+// retrieveAllPages calls fetch for every page of a paginated list endpoint,
+// using the X-Pagination-Pagetotal header returned with the first page to
+// determine how many pages there are, and returns the combined results.
+func retrieveAllPages[T any](fetch pageFetchFunc[T]) ([]T, error) {
+	results := []T{}
+
+	for page, pageTotal := int64(1), int64(1); page <= pageTotal; page++ {
+		items, resp, err := fetch(page, defaultPageSize)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, items...)
+
+		// endpoints which aren't actually paginated don't send the header,
+		// in which case everything was returned in the first page
+		if header := resp.Header.Get("X-Pagination-Pagetotal"); page == 1 && header != "" {
+			pageTotal, err = strconv.ParseInt(header, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
-	return set
+
+	return results, nil
 }
 
 func is200(resp *http.Response) bool {
@@ -136,15 +225,26 @@ func requiredString(d *schema.ResourceData, name string) string {
 	return d.Get(name).(string)
 }
 
-// stringSlicesAreEqual compares two string slices and returns true if they are equal.
-func stringSlicesAreEqual(x, y []string, sortSlices bool) bool {
+// stringSlicesAreEqual compares two string slices and returns true if they are
+// equal, ignoring the order of their elements if ignoreOrder. Neither slice is
+// modified.
+func stringSlicesAreEqual(x, y []string, ignoreOrder bool) bool {
 	if len(x) != len(y) {
 		return false
 	}
 
-	if sortSlices {
-		sort.Strings(x)
-		sort.Strings(y)
+	if ignoreOrder {
+		counts := make(map[string]int, len(x))
+		for _, v := range x {
+			counts[v]++
+		}
+		for _, v := range y {
+			if counts[v] == 0 {
+				return false
+			}
+			counts[v]--
+		}
+		return true
 	}
 
 	for i, v := range x {
@@ -174,6 +274,26 @@ func stringToTime(s string) time.Time {
 	return t
 }
 
+// suppressEquivalentTimes is a DiffSuppressFunc for RFC 3339 timestamps which
+// ignores differences in how the same instant is written, e.g. a different
+// UTC offset, as the API normalizes timestamps before returning them.
+func suppressEquivalentTimes(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return old == new
+	}
+
+	oldTime, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+	newTime, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+
+	return oldTime.Equal(newTime)
+}
+
 // waitFunc should be implemented by callers that want to wait on a particular
 // action
 type waitFunc func() error
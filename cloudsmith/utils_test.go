@@ -0,0 +1,87 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/samber/lo"
+)
+
+func TestSuppressEquivalentTimes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		old, new string
+		expected bool
+	}{
+		{"", "", true},
+		{"2030-01-01T00:00:00Z", "", false},
+		{"", "2030-01-01T00:00:00Z", false},
+		{"2030-01-01T00:00:00Z", "2030-01-01T00:00:00Z", true},
+		{"2030-01-01T00:00:00Z", "2030-01-01T01:00:00+01:00", true},
+		{"2030-01-01T00:00:00Z", "2030-01-01T00:00:01Z", false},
+		{"2030-01-01T00:00:00Z", "not a time", false},
+	}
+
+	for _, test := range tests {
+		if actual := suppressEquivalentTimes("limit_date_range_from", test.old, test.new, nil); actual != test.expected {
+			t.Errorf("suppressEquivalentTimes(%q, %q) = %t, expected %t", test.old, test.new, actual, test.expected)
+		}
+	}
+}
+
+// benchmarkCidrs returns n distinct CIDRs, in no particular order, as might
+// be generated from a data source.
+func benchmarkCidrs(n int) []string {
+	cidrs := make([]string, n)
+	for i := range cidrs {
+		j := (i * 7919) % n
+		cidrs[i] = fmt.Sprintf("10.%d.%d.0/24", j/256%256, j%256)
+	}
+	return cidrs
+}
+
+func BenchmarkExpandStrings(b *testing.B) {
+	r := &schema.Resource{Schema: map[string]*schema.Schema{
+		CidrAllow: {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+	}}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		CidrAllow: lo.ToAnySlice(benchmarkCidrs(10000)),
+	})
+	diff, err := r.Diff(context.Background(), nil, config, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(nil, diff)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		expandStrings(d, CidrAllow)
+	}
+}
+
+func BenchmarkFlattenStrings(b *testing.B) {
+	cidrs := benchmarkCidrs(10000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		flattenStrings(cidrs)
+	}
+}
+
+func BenchmarkStringSlicesAreEqual(b *testing.B) {
+	x := benchmarkCidrs(10000)
+	y := lo.Reverse(benchmarkCidrs(10000))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stringSlicesAreEqual(x, y, true)
+	}
+}
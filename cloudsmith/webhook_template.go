@@ -0,0 +1,223 @@
+package cloudsmith
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// Webhook templates are Handlebars templates. Only their syntax is checked
+// here: that every mustache ({{...}}, {{{...}}} or a comment) is terminated
+// and has an expression, and that block helpers ({{#if data.name}} ...
+// {{/if}}) are properly nested. Which variables are available depends on the
+// payload of each event, which isn't described by the API.
+
+// webhookTemplateError is a syntax error in a webhook template, located by
+// the 1-based line and column of the character at which it was detected.
+type webhookTemplateError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *webhookTemplateError) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d", e.Message, e.Line, e.Column)
+}
+
+// webhookTemplateBlock is a block helper which has been opened but not yet
+// closed.
+type webhookTemplateBlock struct {
+	Name   string
+	Offset int
+}
+
+// webhookTemplateParser checks the syntax of a webhook template, tracking the
+// open blocks as it goes.
+type webhookTemplateParser struct {
+	template string
+	blocks   []webhookTemplateBlock
+}
+
+// errorAt returns an error located at a byte offset in the template.
+func (p *webhookTemplateParser) errorAt(offset int, format string, args ...interface{}) error {
+	before := p.template[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := len([]rune(before[strings.LastIndex(before, "\n")+1:])) + 1
+
+	return &webhookTemplateError{Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+// findClose returns the offset of the first occurrence of delimiter at or after
+// offset which isn't inside a quoted string, or -1 if there is none.
+func (p *webhookTemplateParser) findClose(offset int, delimiter string) int {
+	var quote byte
+	for i := offset; i < len(p.template); i++ {
+		switch c := p.template[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.HasPrefix(p.template[i:], delimiter):
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *webhookTemplateParser) parse() error {
+	for offset := 0; ; {
+		open := strings.Index(p.template[offset:], "{{")
+		if open < 0 {
+			break
+		}
+		open += offset
+
+		// \{{ is an escaped, literal mustache
+		if open > 0 && p.template[open-1] == '\\' {
+			offset = open + 2
+			continue
+		}
+
+		end, err := p.parseMustache(open)
+		if err != nil {
+			return err
+		}
+		offset = end
+	}
+
+	if len(p.blocks) > 0 {
+		block := p.blocks[len(p.blocks)-1]
+		return p.errorAt(block.Offset, "unclosed block %q", block.Name)
+	}
+
+	return nil
+}
+
+// parseMustache checks the mustache starting at offset, returning the offset
+// just after it.
+func (p *webhookTemplateParser) parseMustache(offset int) (int, error) {
+	rest := p.template[offset:]
+
+	// comments may contain anything, including mustaches
+	for _, comment := range [][2]string{{"{{!--", "--}}"}, {"{{~!--", "--}}"}, {"{{!", "}}"}, {"{{~!", "}}"}} {
+		if strings.HasPrefix(rest, comment[0]) {
+			end := strings.Index(p.template[offset+len(comment[0]):], comment[1])
+			if end < 0 {
+				return 0, p.errorAt(offset, "unterminated comment")
+			}
+			return offset + len(comment[0]) + end + len(comment[1]), nil
+		}
+	}
+
+	open, closing := "{{", "}}"
+	if strings.HasPrefix(rest, "{{{") {
+		open, closing = "{{{", "}}}"
+	}
+
+	end := p.findClose(offset+len(open), closing)
+	if nested := p.findClose(offset+len(open), "{{"); end < 0 || (nested >= 0 && nested < end) {
+		return 0, p.errorAt(offset, "unterminated %q", open)
+	}
+
+	expression := p.template[offset+len(open) : end]
+	expression = strings.TrimPrefix(expression, "~")
+	expression = strings.TrimSuffix(expression, "~")
+	expression = strings.TrimSpace(expression)
+
+	if err := p.parseExpression(offset, open, expression); err != nil {
+		return 0, err
+	}
+
+	return end + len(closing), nil
+}
+
+// parseExpression checks the expression of a mustache, opening and closing
+// blocks as required.
+func (p *webhookTemplateParser) parseExpression(offset int, open, expression string) error {
+	if expression == "" {
+		return p.errorAt(offset, "empty expression")
+	}
+
+	// {{{...}}} only outputs unescaped values, it can't open or close blocks
+	if open == "{{{" {
+		if strings.ContainsAny(expression[:1], "#^/>") {
+			return p.errorAt(offset, "invalid expression %q in triple mustache", expression)
+		}
+		return nil
+	}
+
+	switch {
+	case expression == "^" || expression == "else" || strings.HasPrefix(expression, "else "):
+		if len(p.blocks) == 0 {
+			return p.errorAt(offset, "%q outside of a block", expression)
+		}
+	case expression[0] == '#' || expression[0] == '^':
+		name := webhookTemplateBlockName(expression[1:])
+		if name == "" {
+			return p.errorAt(offset, "missing block name in %q", expression)
+		}
+		p.blocks = append(p.blocks, webhookTemplateBlock{Name: name, Offset: offset})
+	case expression[0] == '/':
+		name := webhookTemplateBlockName(expression[1:])
+		if len(p.blocks) == 0 {
+			return p.errorAt(offset, "closing block %q which was never opened", name)
+		}
+		block := p.blocks[len(p.blocks)-1]
+		if name != block.Name {
+			return p.errorAt(offset, "closing block %q, expected %q", name, block.Name)
+		}
+		p.blocks = p.blocks[:len(p.blocks)-1]
+	case expression[0] == '>':
+		if strings.TrimSpace(expression[1:]) == "" {
+			return p.errorAt(offset, "missing partial name")
+		}
+	}
+
+	return nil
+}
+
+// webhookTemplateBlockName returns the name of the helper of a block, i.e. the
+// first word after the #, ^ or / (and the > of partial blocks).
+func webhookTemplateBlockName(expression string) string {
+	fields := strings.Fields(strings.TrimPrefix(expression, ">"))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// parseWebhookTemplate checks the syntax of a webhook template, returning a
+// *webhookTemplateError describing the first error found, if any.
+func parseWebhookTemplate(template string) error {
+	p := &webhookTemplateParser{template: template}
+	return p.parse()
+}
+
+// validateWebhookTemplate is a ValidateDiagFunc for webhook templates, which
+// reports syntax errors at plan time rather than when the first event fires.
+func validateWebhookTemplate(i interface{}, path cty.Path) diag.Diagnostics {
+	template, ok := i.(string)
+	if !ok {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid webhook template",
+			Detail:        fmt.Sprintf("expected type of webhook template to be string, got %T", i),
+			AttributePath: path,
+		}}
+	}
+
+	if err := parseWebhookTemplate(template); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid webhook template",
+			Detail:        err.Error(),
+			AttributePath: path,
+		}}
+	}
+
+	return nil
+}
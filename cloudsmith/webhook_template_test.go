@@ -0,0 +1,74 @@
+//nolint:testpackage
+package cloudsmith
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseWebhookTemplate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []string{
+		"",
+		"created: {{data.name}}: {{data.version}}",
+		"{{#if data.tags}}{{#each data.tags}}{{this}} {{/each}}{{else}}untagged{{/if}}",
+		"{{#unless data.is_sync_completed}}syncing{{^}}synced{{/unless}}",
+		"{{~#if data.name~}} {{{data.description}}} {{~/if~}}",
+		"{{! a comment }}{{!-- a {{#if}} comment --}}",
+		`{{lookup data "}}"}}`,
+		`\{{not a mustache`,
+		"{\n  \"name\": \"{{data.name}}\"\n}",
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc, func(t *testing.T) {
+			t.Parallel()
+
+			if err := parseWebhookTemplate(tc); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestParseWebhookTemplateErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		template string
+		line     int
+		column   int
+	}{
+		{"{{data.name", 1, 1},
+		{"name: {{data.name}\n", 1, 7},
+		{"{{data.name {{data.version}}", 1, 1},
+		{"{{ }}", 1, 1},
+		{"{{#if data.name}}", 1, 1},
+		{"ok\n  {{/if}}", 2, 3},
+		{"{{#if a}}{{#each b}}{{/if}}{{/each}}", 1, 21},
+		{"{{else}}", 1, 1},
+		{"{{{#if a}}}", 1, 1},
+		{"{{!-- unterminated }}", 1, 1},
+		{"{{#}}", 1, 1},
+		{"{{>}}", 1, 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.template, func(t *testing.T) {
+			t.Parallel()
+
+			err := parseWebhookTemplate(tc.template)
+
+			var templateErr *webhookTemplateError
+			if !errors.As(err, &templateErr) {
+				t.Fatalf("expected a webhook template error, got: %v", err)
+			}
+			if templateErr.Line != tc.line || templateErr.Column != tc.column {
+				t.Fatalf("expected error at line %d, column %d, got: %s", tc.line, tc.column, templateErr)
+			}
+		})
+	}
+}
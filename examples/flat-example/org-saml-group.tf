@@ -1,4 +1,4 @@
-resource "cloudsmith_saml" "owners_mapping" {
+resource "cloudsmith_saml_group_sync" "owners_mapping" {
   organization = data.cloudsmith_organization.org-demo.slug
   idp_key = "administrators"
   idp_value = "administrators"
@@ -6,7 +6,7 @@ resource "cloudsmith_saml" "owners_mapping" {
   team = "owners"
 }
 
-resource "cloudsmith_saml" "developers_mapping" {
+resource "cloudsmith_saml_group_sync" "developers_mapping" {
   organization = data.cloudsmith_organization.org-demo.slug
   idp_key = "interns"
   idp_value = "interns"
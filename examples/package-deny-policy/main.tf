@@ -0,0 +1,41 @@
+terraform {
+  required_providers {
+    cloudsmith = {
+      source = "cloudsmith-io/cloudsmith"
+    }
+  }
+}
+
+# The API key is read from the CLOUDSMITH_API_KEY environment variable.
+provider "cloudsmith" {}
+
+variable "namespace" {
+  type        = string
+  description = "The namespace (organization) in which to create the policy."
+}
+
+variable "name" {
+  type        = string
+  description = "The name of the policy, and of the package it blocks."
+  default     = "block-leftpad"
+}
+
+resource "cloudsmith_package_deny_policy" "this" {
+  name          = var.name
+  namespace     = var.namespace
+  description   = "Blocks every version of the ${var.name} package."
+  package_query = "name:^${var.name}$"
+}
+
+# Check which policies would block the package, once the policy exists.
+data "cloudsmith_package_deny_policy_preview" "blocked" {
+  namespace       = var.namespace
+  package_name    = var.name
+  package_version = "1.0.0"
+
+  depends_on = [cloudsmith_package_deny_policy.this]
+}
+
+output "blocking_policies" {
+  value = data.cloudsmith_package_deny_policy_preview.blocked.matches[*].name
+}
@@ -0,0 +1,11 @@
+run "create" {
+  assert {
+    condition     = cloudsmith_package_deny_policy.this.enabled
+    error_message = "The policy should be enabled by default."
+  }
+
+  assert {
+    condition     = contains(data.cloudsmith_package_deny_policy_preview.blocked.matches[*].name, var.name)
+    error_message = "The policy should block the package it is named after."
+  }
+}
@@ -0,0 +1,45 @@
+terraform {
+  required_providers {
+    cloudsmith = {
+      source = "cloudsmith-io/cloudsmith"
+    }
+  }
+}
+
+# The API key is read from the CLOUDSMITH_API_KEY environment variable.
+provider "cloudsmith" {}
+
+variable "namespace" {
+  type        = string
+  description = "The namespace (organization) in which to create the repository."
+}
+
+variable "name" {
+  type        = string
+  description = "The name of the repository."
+  default     = "my-repository"
+}
+
+resource "cloudsmith_repository" "this" {
+  name            = var.name
+  namespace       = var.namespace
+  description     = "Private repository with a read-only token for Docker and Helm."
+  repository_type = "Private"
+}
+
+# An entitlement token which can only download Docker images and Helm charts.
+resource "cloudsmith_entitlement" "ci" {
+  name                = "${var.name}-ci"
+  namespace           = cloudsmith_repository.this.namespace
+  repository          = cloudsmith_repository.this.slug_perm
+  limit_package_query = "format:docker OR format:helm"
+}
+
+output "repository_slug" {
+  value = cloudsmith_repository.this.slug
+}
+
+output "token" {
+  value     = cloudsmith_entitlement.ci.token
+  sensitive = true
+}
@@ -0,0 +1,16 @@
+run "create" {
+  assert {
+    condition     = cloudsmith_repository.this.slug == var.name
+    error_message = "The repository slug should be derived from its name."
+  }
+
+  assert {
+    condition     = cloudsmith_entitlement.ci.is_active
+    error_message = "The entitlement token should be active."
+  }
+
+  assert {
+    condition     = length(cloudsmith_entitlement.ci.token) > 0
+    error_message = "The entitlement token should be set."
+  }
+}
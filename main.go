@@ -9,4 +9,7 @@ func main() {
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: cloudsmith.Provider,
 	})
+
+	// Terraform shuts the plugin down at the end of a run
+	cloudsmith.LogAPIUsageSummaries()
 }